@@ -0,0 +1,123 @@
+package envstruct_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+)
+
+type BenchmarkDBConfig struct {
+	Host     string                   `env:"BENCHMARK_HOST,required,report"`
+	Port     uint16                   `env:"BENCHMARK_PORT,report"`
+	Password envstruct.Secret[string] `env:"BENCHMARK_PASSWORD"`
+}
+
+type BenchmarkStruct struct {
+	Name    string            `env:"BENCHMARK_NAME,required,report"`
+	Debug   bool              `env:"BENCHMARK_DEBUG,report"`
+	Count   int               `env:"BENCHMARK_COUNT,report"`
+	Ratio   float64           `env:"BENCHMARK_RATIO,report"`
+	Level   string            `env:"BENCHMARK_LEVEL,enum=debug|info,report"`
+	Timeout time.Duration     `env:"BENCHMARK_TIMEOUT,report"`
+	Hosts   []string          `env:"BENCHMARK_HOSTS,report"`
+	Labels  map[string]string `env:"BENCHMARK_LABELS,report"`
+	DB      BenchmarkDBConfig
+	Replica *BenchmarkDBConfig `env:",optional" envprefix:"REPLICA_"`
+}
+
+func setBenchmarkEnv(b *testing.B) {
+	for k, v := range map[string]string{
+		"BENCHMARK_NAME":     "app",
+		"BENCHMARK_DEBUG":    "true",
+		"BENCHMARK_COUNT":    "12",
+		"BENCHMARK_RATIO":    "0.5",
+		"BENCHMARK_LEVEL":    "info",
+		"BENCHMARK_TIMEOUT":  "3s",
+		"BENCHMARK_HOSTS":    "a,b,c",
+		"BENCHMARK_LABELS":   "a:1,b:2",
+		"BENCHMARK_HOST":     "db",
+		"BENCHMARK_PORT":     "5432",
+		"BENCHMARK_PASSWORD": "secret",
+	} {
+		b.Setenv(k, v)
+	}
+}
+
+func BenchmarkLoad(b *testing.B) {
+	setBenchmarkEnv(b)
+
+	load := func(b *testing.B, reset func()) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			reset()
+
+			var s BenchmarkStruct
+			if err := envstruct.Load(&s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	b.Run("cached", func(b *testing.B) { load(b, func() {}) })
+	b.Run("uncached", func(b *testing.B) { load(b, envstruct.ResetPlans) })
+}
+
+func BenchmarkLoadParallel(b *testing.B) {
+	setBenchmarkEnv(b)
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			var s BenchmarkStruct
+			if err := envstruct.Load(&s); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkToEnv(b *testing.B) {
+	setBenchmarkEnv(b)
+
+	var s BenchmarkStruct
+	if err := envstruct.Load(&s); err != nil {
+		b.Fatal(err)
+	}
+
+	toEnv := func(b *testing.B, reset func()) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			reset()
+			envstruct.ToEnv(&s)
+		}
+	}
+
+	b.Run("cached", func(b *testing.B) { toEnv(b, func() {}) })
+	b.Run("uncached", func(b *testing.B) { toEnv(b, envstruct.ResetPlans) })
+}
+
+func BenchmarkWriteReport(b *testing.B) {
+	setBenchmarkEnv(b)
+
+	var s BenchmarkStruct
+	if err := envstruct.Load(&s); err != nil {
+		b.Fatal(err)
+	}
+
+	defer func(w io.Writer) { envstruct.ReportWriter = w }(envstruct.ReportWriter)
+	envstruct.ReportWriter = io.Discard
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := envstruct.WriteReport(&s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
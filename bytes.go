@@ -0,0 +1,98 @@
+package envstruct
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"code.cloudfoundry.org/go-envstruct/internal/tags"
+)
+
+// Values of the `encoding` tag option for []byte and [N]byte fields.
+const (
+	encodingBase64    = tags.EncodingBase64
+	encodingBase64URL = tags.EncodingBase64URL
+	encodingHex       = tags.EncodingHex
+)
+
+var byteType = reflect.TypeOf(byte(0))
+
+// isBytes returns true for []byte and [N]byte types, which are decoded from
+// a single encoded value rather than a comma separated list.
+func isBytes(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) &&
+		t.Elem() == byteType
+}
+
+func setBytes(value reflect.Value, input string, tagProperties []string) error {
+	b, err := decodeBytes(input, bytesEncoding(tagProperties))
+	if err != nil {
+		return err
+	}
+
+	if value.Kind() == reflect.Array {
+		if len(b) != value.Len() {
+			return fmt.Errorf("expected %d bytes, found %d", value.Len(), len(b))
+		}
+
+		reflect.Copy(value, reflect.ValueOf(b))
+
+		return nil
+	}
+
+	value.SetBytes(b)
+
+	return nil
+}
+
+func formatBytes(value reflect.Value, tagProperties []string) string {
+	if !value.CanInterface() {
+		return omittedValue
+	}
+
+	b := make([]byte, value.Len())
+	reflect.Copy(reflect.ValueOf(b), value)
+
+	switch bytesEncoding(tagProperties) {
+	case encodingHex:
+		return hex.EncodeToString(b)
+	case encodingBase64URL:
+		return base64.URLEncoding.EncodeToString(b)
+	}
+
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// bytesEncoding returns the encoding given with the `encoding` tag option,
+// or standard base64 if there is none.
+func bytesEncoding(tagProperties []string) string {
+	if encoding, ok := tagPropertyValue(tagProperties, tagEncoding); ok {
+		return encoding
+	}
+
+	return encodingBase64
+}
+
+func decodeBytes(input, encoding string) ([]byte, error) {
+	switch encoding {
+	case encodingBase64:
+		return decodeBase64(input, base64.StdEncoding)
+	case encodingBase64URL:
+		return decodeBase64(input, base64.URLEncoding)
+	case encodingHex:
+		return hex.DecodeString(input)
+	}
+
+	return nil, fmt.Errorf("unsupported encoding %q", encoding)
+}
+
+// decodeBase64 decodes padded as well as unpadded input.
+func decodeBase64(input string, enc *base64.Encoding) ([]byte, error) {
+	if strings.HasSuffix(input, "=") {
+		return enc.DecodeString(input)
+	}
+
+	return enc.WithPadding(base64.NoPadding).DecodeString(input)
+}
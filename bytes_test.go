@@ -0,0 +1,132 @@
+package envstruct_test
+
+import (
+	"bytes"
+	"os"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type BytesTestStruct struct {
+	Key       []byte   `env:"BYTES_KEY,report"`
+	URLKey    []byte   `env:"BYTES_URL_KEY,encoding=base64url,report"`
+	Salt      []byte   `env:"BYTES_SALT,encoding=hex,report"`
+	FixedKey  [4]byte  `env:"BYTES_FIXED_KEY,encoding=hex,report"`
+	HiddenKey [4]byte  `env:"BYTES_HIDDEN_KEY,encoding=hex"`
+	Keys      [][]byte `env:"BYTES_KEYS,report"`
+}
+
+var bytesEnvVars = map[string]string{
+	"BYTES_KEY":        "3q2+7w==",
+	"BYTES_URL_KEY":    "3q2-7w",
+	"BYTES_SALT":       "deadbeef",
+	"BYTES_FIXED_KEY":  "01020304",
+	"BYTES_HIDDEN_KEY": "cafebabe",
+	"BYTES_KEYS":       "AQI=,AwQ=",
+}
+
+var _ = Describe("byte types", func() {
+	var ts BytesTestStruct
+
+	BeforeEach(func() {
+		for k, v := range bytesEnvVars {
+			os.Setenv(k, v)
+		}
+
+		ts = BytesTestStruct{}
+	})
+
+	AfterEach(func() {
+		for k := range bytesEnvVars {
+			os.Unsetenv(k)
+		}
+	})
+
+	It("decodes the values", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(ts.Key).To(Equal([]byte{0xde, 0xad, 0xbe, 0xef}))
+		Expect(ts.URLKey).To(Equal([]byte{0xde, 0xad, 0xbe, 0xef}))
+		Expect(ts.Salt).To(Equal([]byte{0xde, 0xad, 0xbe, 0xef}))
+		Expect(ts.FixedKey).To(Equal([4]byte{1, 2, 3, 4}))
+		Expect(ts.HiddenKey).To(Equal([4]byte{0xca, 0xfe, 0xba, 0xbe}))
+		Expect(ts.Keys).To(Equal([][]byte{{1, 2}, {3, 4}}))
+	})
+
+	It("decodes unpadded base64", func() {
+		os.Setenv("BYTES_KEY", "3q2+7w")
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+		Expect(ts.Key).To(Equal([]byte{0xde, 0xad, 0xbe, 0xef}))
+	})
+
+	DescribeTable("returns an error for invalid values",
+		func(envVar, value string) {
+			os.Setenv(envVar, value)
+
+			Expect(envstruct.Load(&ts)).ToNot(Succeed())
+		},
+		Entry("base64", "BYTES_KEY", "not base64!"),
+		Entry("base64url", "BYTES_URL_KEY", "3q2+7w=="),
+		Entry("hex", "BYTES_SALT", "xyz"),
+		Entry("too few bytes for an array", "BYTES_FIXED_KEY", "010203"),
+		Entry("too many bytes for an array", "BYTES_FIXED_KEY", "0102030405"),
+	)
+
+	It("returns an error for an unsupported encoding", func() {
+		var withUnsupportedEncoding struct {
+			Key []byte `env:"BYTES_KEY,encoding=base32"`
+		}
+
+		Expect(envstruct.Load(&withUnsupportedEncoding)).To(MatchError(ContainSubstring(`unsupported encoding "base32"`)))
+	})
+
+	It("encodes the values with ToEnv", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(envstruct.ToEnv(&ts)).To(ConsistOf(
+			"BYTES_KEY=3q2+7w==",
+			"BYTES_URL_KEY=3q2-7w==",
+			"BYTES_SALT=deadbeef",
+			"BYTES_FIXED_KEY=01020304",
+			"BYTES_HIDDEN_KEY=cafebabe",
+			"BYTES_KEYS=AQI=,AwQ=",
+		))
+	})
+
+	It("never displays raw bytes in the report", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		outputBuffer := bytes.NewBuffer(nil)
+		envstruct.ReportWriter = outputBuffer
+
+		Expect(envstruct.WriteReport(&ts)).To(Succeed())
+		Expect(outputBuffer.String()).To(Equal(expectedBytesReportOutput))
+	})
+
+	It("does not panic on unexported fields", func() {
+		s := struct {
+			key   []byte  `env:"BYTES_KEY,report"`
+			fixed [4]byte `env:"BYTES_FIXED_KEY,report"`
+		}{[]byte("key"), [4]byte{1, 2, 3, 4}}
+
+		envstruct.ReportWriter = bytes.NewBuffer(nil)
+
+		Expect(func() { envstruct.ToEnv(&s) }).ToNot(Panic())
+		Expect(func() { envstruct.WriteReport(&s) }).ToNot(Panic())
+	})
+})
+
+const (
+	expectedBytesReportOutput = `FIELD NAME:                TYPE:      ENV:              REQUIRED:  VALUE:
+BytesTestStruct.Key        []uint8    BYTES_KEY         false      3q2+7w==
+BytesTestStruct.URLKey     []uint8    BYTES_URL_KEY     false      3q2-7w==
+BytesTestStruct.Salt       []uint8    BYTES_SALT        false      deadbeef
+BytesTestStruct.FixedKey   [4]uint8   BYTES_FIXED_KEY   false      01020304
+BytesTestStruct.HiddenKey  [4]uint8   BYTES_HIDDEN_KEY  false      (OMITTED)
+BytesTestStruct.Keys       [][]uint8  BYTES_KEYS        false      [AQI= AwQ=]
+`
+)
@@ -0,0 +1,166 @@
+package envstruct
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a number of bytes. Load parses it from a number followed by an
+// optional unit, e.g. `10MB`, `512KiB` or `1.5Gi`. Units are case
+// insensitive. Decimal units (`KB`, `MB`, `GB`, `TB`, `PB`, `EB` or `K`, `M`,
+// `G`, ... as in Kubernetes quantities) are powers of 1000 and binary units
+// (`KiB`, `MiB`, `GiB`, ... or `Ki`, `Mi`, `Gi`, ...) are powers of 1024.
+type ByteSize uint64
+
+// Common byte sizes.
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+	EB ByteSize = 1000 * PB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+	PiB ByteSize = 1024 * TiB
+	EiB ByteSize = 1024 * PiB
+)
+
+type byteUnit struct {
+	name string
+	size ByteSize
+}
+
+// byteUnits is ordered from the largest to the smallest unit.
+var byteUnits = []byteUnit{
+	{"EiB", EiB},
+	{"EB", EB},
+	{"PiB", PiB},
+	{"PB", PB},
+	{"TiB", TiB},
+	{"TB", TB},
+	{"GiB", GiB},
+	{"GB", GB},
+	{"MiB", MiB},
+	{"MB", MB},
+	{"KiB", KiB},
+	{"KB", KB},
+	{"B", Byte},
+}
+
+var byteUnitAliases = map[string]ByteSize{
+	"":  Byte,
+	"k": KB,
+	"m": MB,
+	"g": GB,
+	"t": TB,
+	"p": PB,
+	"e": EB,
+
+	"ki": KiB,
+	"mi": MiB,
+	"gi": GiB,
+	"ti": TiB,
+	"pi": PiB,
+	"ei": EiB,
+}
+
+// ParseByteSize parses a byte size such as `10MB` or `512Ki`. Fractions are
+// allowed as long as they amount to a whole number of bytes, e.g. `1.5KiB`
+// but not `1.5B`.
+func ParseByteSize(s string) (ByteSize, error) {
+	input := strings.TrimSpace(s)
+
+	i := strings.IndexFunc(input, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != '_'
+	})
+	if i < 0 {
+		i = len(input)
+	}
+
+	number := input[:i]
+	unit := strings.ToLower(strings.TrimSpace(input[i:]))
+
+	size, ok := byteUnitAliases[unit]
+	if !ok {
+		for _, u := range byteUnits {
+			if strings.ToLower(u.name) == unit {
+				size, ok = u.size, true
+				break
+			}
+		}
+	}
+	if !ok || number == "" {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+
+	n, ok := new(big.Rat).SetString(strings.ReplaceAll(number, "_", ""))
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+
+	n.Mul(n, new(big.Rat).SetUint64(uint64(size)))
+	if !n.IsInt() {
+		return 0, fmt.Errorf("byte size %q is not a whole number of bytes", s)
+	}
+
+	bytes := n.Num()
+	if !bytes.IsUint64() {
+		return 0, fmt.Errorf("byte size %q overflows", s)
+	}
+
+	return ByteSize(bytes.Uint64()), nil
+}
+
+// UnmarshalEnv implements Unmarshaller.
+func (b *ByteSize) UnmarshalEnv(v string) error {
+	size, err := ParseByteSize(v)
+	if err != nil {
+		return err
+	}
+
+	*b = size
+
+	return nil
+}
+
+// String returns the size in a human readable form, e.g. `1.5 GiB`.
+func (b ByteSize) String() string {
+	for _, u := range byteUnits {
+		if u.size == Byte || !strings.HasSuffix(u.name, "iB") {
+			continue
+		}
+
+		if b >= u.size {
+			n := strconv.FormatFloat(float64(b)/float64(u.size), 'f', 2, 64)
+			n = strings.TrimSuffix(strings.TrimRight(n, "0"), ".")
+			return n + " " + u.name
+		}
+	}
+
+	return strconv.FormatUint(uint64(b), 10) + " B"
+}
+
+// Canonical returns the size using the largest unit it is an exact multiple
+// of, e.g. `10MiB`. It is parsed back to the same size by ParseByteSize.
+func (b ByteSize) Canonical() string {
+	for _, u := range byteUnits {
+		if b != 0 && b%u.size == 0 {
+			return strconv.FormatUint(uint64(b/u.size), 10) + u.name
+		}
+	}
+
+	return "0B"
+}
+
+func formatByteSize(value reflect.Value, _ []string) string {
+	return ByteSize(value.Uint()).Canonical()
+}
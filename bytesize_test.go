@@ -0,0 +1,126 @@
+package envstruct_test
+
+import (
+	"bytes"
+	"os"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type ByteSizeTestStruct struct {
+	MaxBody     envstruct.ByteSize   `env:"MAX_BODY,report"`
+	MemoryLimit envstruct.ByteSize   `env:"MEMORY_LIMIT,report"`
+	Buffers     []envstruct.ByteSize `env:"BUFFERS,report"`
+}
+
+var _ = Describe("ByteSize", func() {
+	DescribeTable("ParseByteSize()",
+		func(input string, expected envstruct.ByteSize) {
+			size, err := envstruct.ParseByteSize(input)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(size).To(Equal(expected))
+		},
+		Entry("bytes", "512", envstruct.ByteSize(512)),
+		Entry("bytes with unit", "512B", envstruct.ByteSize(512)),
+		Entry("KB", "10KB", 10*envstruct.KB),
+		Entry("KiB", "10KiB", 10*envstruct.KiB),
+		Entry("MB", "10MB", 10*envstruct.MB),
+		Entry("MiB", "10MiB", 10*envstruct.MiB),
+		Entry("GB", "2GB", 2*envstruct.GB),
+		Entry("GiB", "2GiB", 2*envstruct.GiB),
+		Entry("lower case", "10mib", 10*envstruct.MiB),
+		Entry("Kubernetes decimal", "500k", 500*envstruct.KB),
+		Entry("Kubernetes binary", "256Mi", 256*envstruct.MiB),
+		Entry("fraction", "1.5GiB", 1536*envstruct.MiB),
+		Entry("decimal fraction", "1.1MB", 1100*envstruct.KB),
+		Entry("space", "10 MB", 10*envstruct.MB),
+		Entry("underscores", "1_000KB", envstruct.MB),
+	)
+
+	DescribeTable("ParseByteSize() errors",
+		func(input string) {
+			_, err := envstruct.ParseByteSize(input)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty", ""),
+		Entry("no number", "MB"),
+		Entry("unknown unit", "10XB"),
+		Entry("negative", "-10MB"),
+		Entry("overflow", "100EiB"),
+		Entry("fraction of a byte", "1.5B"),
+		Entry("fraction of a byte with a unit", "0.1KiB"),
+	)
+
+	DescribeTable("String()",
+		func(size envstruct.ByteSize, expected string) {
+			Expect(size.String()).To(Equal(expected))
+		},
+		Entry("bytes", envstruct.ByteSize(512), "512 B"),
+		Entry("KiB", 10*envstruct.KiB, "10 KiB"),
+		Entry("fraction", 1536*envstruct.MiB, "1.5 GiB"),
+		Entry("decimal", 10*envstruct.MB, "9.54 MiB"),
+	)
+
+	DescribeTable("Canonical()",
+		func(size envstruct.ByteSize, expected string) {
+			Expect(size.Canonical()).To(Equal(expected))
+		},
+		Entry("zero", envstruct.ByteSize(0), "0B"),
+		Entry("bytes", envstruct.ByteSize(512), "512B"),
+		Entry("binary", 10*envstruct.MiB, "10MiB"),
+		Entry("decimal", 10*envstruct.MB, "10MB"),
+		Entry("fraction", 1536*envstruct.MiB, "1536MiB"),
+	)
+
+	Context("with a struct", func() {
+		var ts ByteSizeTestStruct
+
+		BeforeEach(func() {
+			os.Setenv("MAX_BODY", "10MB")
+			os.Setenv("MEMORY_LIMIT", "1.5Gi")
+			os.Setenv("BUFFERS", "4KiB,64KiB")
+
+			ts = ByteSizeTestStruct{}
+			Expect(envstruct.Load(&ts)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("MAX_BODY")
+			os.Unsetenv("MEMORY_LIMIT")
+			os.Unsetenv("BUFFERS")
+		})
+
+		It("is populated by Load", func() {
+			Expect(ts.MaxBody).To(Equal(10 * envstruct.MB))
+			Expect(ts.MemoryLimit).To(Equal(1536 * envstruct.MiB))
+			Expect(ts.Buffers).To(Equal([]envstruct.ByteSize{4 * envstruct.KiB, 64 * envstruct.KiB}))
+		})
+
+		It("returns an error for an invalid value", func() {
+			os.Setenv("MAX_BODY", "10 parsecs")
+
+			Expect(envstruct.Load(&ts)).ToNot(Succeed())
+		})
+
+		It("is formatted canonically by ToEnv", func() {
+			Expect(envstruct.ToEnv(&ts)).To(ConsistOf(
+				"MAX_BODY=10MB",
+				"MEMORY_LIMIT=1536MiB",
+				"BUFFERS=4KiB,64KiB",
+			))
+		})
+
+		It("is displayed in human form by WriteReport", func() {
+			outputBuffer := bytes.NewBuffer(nil)
+			envstruct.ReportWriter = outputBuffer
+
+			Expect(envstruct.WriteReport(&ts)).To(Succeed())
+			Expect(outputBuffer.String()).To(ContainSubstring("9.54 MiB"))
+			Expect(outputBuffer.String()).To(ContainSubstring("1.5 GiB"))
+			Expect(outputBuffer.String()).To(ContainSubstring("[4 KiB 64 KiB]"))
+		})
+	})
+})
@@ -0,0 +1,208 @@
+// Command envstruct prints the environment variables a struct that is setup
+// for envstruct reads, from the Go source of its package. The service does
+// not need to be compiled or run.
+//
+// Usage:
+//
+//	envstruct -type Config [-format markdown|json|env] [package]
+//
+// The package defaults to the package in the current directory. The formats
+// are a Markdown table, a JSON array and a sample .env file.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/types"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"code.cloudfoundry.org/go-envstruct/internal/gotypes"
+	"code.cloudfoundry.org/go-envstruct/internal/tags"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "envstruct: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// envVar describes an environment variable read by a field.
+type envVar struct {
+	EnvVar      string   `json:"env"`
+	Field       string   `json:"field"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required"`
+	Secret      bool     `json:"secret"`
+	Description string   `json:"description,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+}
+
+func run(args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("envstruct", flag.ContinueOnError)
+	typeName := flags.String("type", "", "name of the struct type (required)")
+	format := flags.String("format", "markdown", "output format: markdown, json or env")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *typeName == "" {
+		return errors.New("-type is required")
+	}
+
+	pattern := "."
+	if flags.NArg() > 0 {
+		pattern = flags.Arg(0)
+	}
+
+	vars, err := load(pattern, *typeName)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "markdown":
+		return writeMarkdown(w, vars)
+	case "json":
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(vars)
+	case "env":
+		return writeEnv(w, vars)
+	}
+
+	return fmt.Errorf("unsupported format %q", *format)
+}
+
+func load(pattern, typeName string) ([]envVar, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+	}
+
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("pattern %q matches %d packages, expected 1", pattern, len(pkgs))
+	}
+
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, pkg.Errors[0]
+	}
+
+	obj, ok := pkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in %s", typeName, pkg.PkgPath)
+	}
+
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("type %s is not a struct", typeName)
+	}
+
+	qualifier := func(p *types.Package) string {
+		if p == pkg.Types {
+			return ""
+		}
+
+		return p.Name()
+	}
+
+	var vars []envVar
+	for _, f := range gotypes.NewRules(pkg.TypesInfo, nil).StructFields(st, typeName) {
+		v := envVar{
+			EnvVar:      f.EnvVar,
+			Field:       f.Path,
+			Type:        types.TypeString(f.Var.Type(), qualifier),
+			Description: f.Tag.Get(tags.Description),
+			Secret:      gotypes.IsSecret(f.Var.Type()),
+		}
+
+		for _, option := range f.Options {
+			key, value, _ := strings.Cut(option, "=")
+			switch key {
+			case tags.Required:
+				v.Required = true
+			case tags.Mask, tags.Hash:
+				v.Secret = true
+			case tags.Enum:
+				v.Enum = strings.Split(value, "|")
+			}
+		}
+
+		vars = append(vars, v)
+	}
+
+	return vars, nil
+}
+
+func writeMarkdown(w io.Writer, vars []envVar) error {
+	fmt.Fprintln(w, "| Variable | Type | Required | Description |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- |")
+
+	for _, v := range vars {
+		var notes []string
+		if v.Description != "" {
+			notes = append(notes, strings.ReplaceAll(v.Description, "\n", " "))
+		}
+		if len(v.Enum) > 0 {
+			notes = append(notes, "One of `"+strings.Join(v.Enum, "`, `")+"`.")
+		}
+		if v.Secret {
+			notes = append(notes, "Secret.")
+		}
+
+		_, err := fmt.Fprintf(w, "| `%s` | `%s` | %t | %s |\n",
+			v.EnvVar,
+			v.Type,
+			v.Required,
+			strings.ReplaceAll(strings.Join(notes, "<br>"), "|", `\|`),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeEnv(w io.Writer, vars []envVar) error {
+	for i, v := range vars {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		if v.Description != "" {
+			fmt.Fprintf(w, "# %s\n", strings.ReplaceAll(v.Description, "\n", "\n# "))
+		}
+
+		var notes []string
+		notes = append(notes, v.Type)
+		if v.Required {
+			notes = append(notes, "required")
+		}
+		if v.Secret {
+			notes = append(notes, "secret")
+		}
+		if len(v.Enum) > 0 {
+			notes = append(notes, "one of "+strings.Join(v.Enum, ", "))
+		}
+
+		fmt.Fprintf(w, "# %s\n", strings.Join(notes, ", "))
+
+		if _, err := fmt.Fprintf(w, "%s=\n", v.EnvVar); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
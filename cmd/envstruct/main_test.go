@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "markdown",
+			want: "| Variable | Type | Required | Description |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| `LOG_LEVEL` | `string` | false | log level<br>One of `debug`, `info`. |\n" +
+				"| `TIMEOUT` | `time.Duration` | false |  |\n" +
+				"| `TOKEN` | `string` | false | Secret. |\n" +
+				"| `DB_HOST` | `string` | true | database host |\n" +
+				"| `DB_PASSWORD` | `envstruct.Secret[string]` | false | Secret. |\n",
+		},
+		{
+			format: "env",
+			want: "# log level\n# string, one of debug, info\nLOG_LEVEL=\n\n" +
+				"# time.Duration\nTIMEOUT=\n\n" +
+				"# string, secret\nTOKEN=\n\n" +
+				"# database host\n# string, required\nDB_HOST=\n\n" +
+				"# envstruct.Secret[string], secret\nDB_PASSWORD=\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := run([]string{"-type", "Config", "-format", tt.format, "./testdata/config"}, &buf); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := run([]string{"-type", "Config", "-format", "json", "./testdata/config"}, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`"env": "DB_HOST"`,
+		`"field": "Config.DB.Host"`,
+		`"enum": [`,
+	} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Errorf("output does not contain %s:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := map[string][]string{
+		"-type is required":               {"./testdata/config"},
+		"type Missing not found":          {"-type", "Missing", "./testdata/config"},
+		"type NotAStruct is not a struct": {"-type", "NotAStruct", "./testdata/config"},
+		`unsupported format "yaml"`:       {"-type", "Config", "-format", "yaml", "./testdata/config"},
+	}
+
+	for want, args := range tests {
+		err := run(args, &bytes.Buffer{})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("run(%q) = %v, want error containing %q", args, err, want)
+		}
+	}
+}
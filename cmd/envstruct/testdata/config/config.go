@@ -0,0 +1,22 @@
+package config
+
+import (
+	"time"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+)
+
+type Database struct {
+	Host     string                   `env:"HOST,required" description:"database host"`
+	Password envstruct.Secret[string] `env:"PASSWORD"`
+}
+
+type Config struct {
+	LogLevel string        `env:"LOG_LEVEL,enum=debug|info" description:"log level"`
+	Timeout  time.Duration `env:"TIMEOUT"`
+	Token    string        `env:"TOKEN,mask=last4"`
+	DB       *Database     `envprefix:"DB_"`
+	internal string        `env:"INTERNAL"`
+}
+
+type NotAStruct int
@@ -0,0 +1,456 @@
+// Command envstructgen generates EnvstructLoadEnv, EnvstructToEnv and
+// EnvstructReport methods for a struct that is setup for envstruct.
+// envstruct.Load, envstruct.ToEnv and the report functions use the generated
+// methods when they are present, which avoids walking the struct with
+// reflection on every call.
+//
+// Fields of type string, bool and of the built-in integer and floating point
+// types are loaded by generated code, which uses parsers and formatters
+// registered with envstruct for those types. Fields of other types are
+// loaded with envstruct.LoadField, so they behave exactly as they would
+// without the generated methods. EnvstructReport lists the fields of the
+// struct and of its nested structs, which envstruct then reports as it
+// would otherwise.
+//
+// The generated code includes a fingerprint of the struct tags. envstruct.Load
+// returns an error if the struct tags have changed since the code was
+// generated.
+//
+// Usage:
+//
+//	//go:generate go run code.cloudfoundry.org/go-envstruct/cmd/envstructgen -type Config
+//
+// The methods are written to <type>_envstruct.go in the package directory,
+// unless a different file is given with -output.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"code.cloudfoundry.org/go-envstruct/internal/gotypes"
+	"code.cloudfoundry.org/go-envstruct/internal/tags"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "envstructgen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("envstructgen", flag.ContinueOnError)
+	typeName := flags.String("type", "", "name of the struct type (required)")
+	output := flags.String("output", "", "output file (default <type>_envstruct.go in the package directory)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *typeName == "" {
+		return errors.New("-type is required")
+	}
+
+	pattern := "."
+	if flags.NArg() > 0 {
+		pattern = flags.Arg(0)
+	}
+
+	pkg, err := loadPackage(pattern)
+	if err != nil {
+		return err
+	}
+
+	src, err := generate(pkg, *typeName)
+	if err != nil {
+		return err
+	}
+
+	path := *output
+	if path == "" {
+		if len(pkg.GoFiles) == 0 {
+			return fmt.Errorf("package %s has no Go files", pkg.PkgPath)
+		}
+
+		path = filepath.Join(filepath.Dir(pkg.GoFiles[0]), strings.ToLower(*typeName)+"_envstruct.go")
+	}
+
+	return os.WriteFile(path, src, 0o644)
+}
+
+func loadPackage(pattern string) (*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+	}
+
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("pattern %q matches %d packages, expected 1", pattern, len(pkgs))
+	}
+
+	// Errors in previously generated files are expected when the struct
+	// has changed, as they are about to be replaced.
+	for _, err := range pkgs[0].Errors {
+		if !inGeneratedFile(err) {
+			return nil, err
+		}
+	}
+
+	return pkgs[0], nil
+}
+
+// inGeneratedFile returns true if err only reports problems in files
+// generated by envstructgen. Build errors list one problem per line.
+func inGeneratedFile(err packages.Error) bool {
+	if err.Pos != "" {
+		return strings.Contains(err.Pos, "_envstruct.go:")
+	}
+
+	for _, line := range strings.Split(err.Msg, "\n") {
+		if !strings.HasPrefix(line, "# ") && !strings.Contains(line, "_envstruct.go:") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// field is a field of the struct, or of a nested struct, that the generated
+// methods handle.
+type field struct {
+	access  string
+	tag     string
+	envVar  string
+	prefix  string
+	options []string
+	basic   *types.Basic
+}
+
+// inline returns true if the field is loaded by generated code rather than
+// by envstruct.LoadField.
+func (f field) inline() bool {
+	if f.basic == nil {
+		return false
+	}
+
+	for _, option := range f.options {
+		if option != tags.Required && option != tags.Report && option != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f field) required() bool {
+	for _, option := range f.options {
+		if option == tags.Required {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reportField is a field listed by the generated EnvstructReport method.
+type reportField struct {
+	structAccess string
+	name         string
+	path         string
+	prefix       string
+}
+
+type generator struct {
+	pkg          *types.Package
+	rules        *gotypes.Rules
+	fields       []field
+	reportFields []reportField
+	fingerprint  string
+}
+
+func generate(pkg *packages.Package, typeName string) ([]byte, error) {
+	obj, ok := pkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in %s", typeName, pkg.PkgPath)
+	}
+
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("type %s is not a struct", typeName)
+	}
+
+	g := &generator{
+		pkg:   pkg.Types,
+		rules: gotypes.NewRules(pkg.TypesInfo, nil),
+	}
+	g.collect(st, true, "c", "", map[*types.Struct]bool{})
+	g.collectReport(st, "c", typeName, "")
+	g.fingerprint = tags.Fingerprint(taggedFields(st, "", nil))
+
+	var b bytes.Buffer
+	g.write(&b, pkg.Name, typeName)
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+
+	return src, nil
+}
+
+// collect adds the fields of st, following the same rules as envstruct.Load.
+// Nested structs are flattened unless they are declared by envstruct, as
+// those might validate themselves once loaded. Fields that can not be
+// accessed from the generated code are skipped, as Load can not set them
+// either.
+func (g *generator) collect(st *types.Struct, local bool, access, prefix string, visiting map[*types.Struct]bool) {
+	visiting[st] = true
+	defer delete(visiting, st)
+
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		structTag := reflect.StructTag(st.Tag(i))
+		properties := tags.Split(structTag.Get(tags.Env))
+
+		if !v.Exported() && !(local && v.Embedded()) {
+			continue
+		}
+
+		f := field{
+			access:  access + "." + v.Name(),
+			tag:     st.Tag(i),
+			prefix:  prefix,
+			options: properties[1:],
+		}
+
+		if name := properties[0]; name != "" {
+			if !v.Exported() {
+				continue
+			}
+
+			f.envVar = prefix + name
+			if basic, ok := v.Type().(*types.Basic); ok && isInlineKind(basic) {
+				f.basic = basic
+			}
+
+			g.fields = append(g.fields, f)
+			continue
+		}
+
+		if nested, ok := g.flattened(v.Type()); ok && !visiting[nested] {
+			named, _ := v.Type().(*types.Named)
+			nestedLocal := named == nil || named.Obj().Pkg() == g.pkg
+
+			g.collect(nested, nestedLocal, f.access, prefix+structTag.Get(tags.EnvPrefix), visiting)
+			continue
+		}
+
+		if v.Exported() && g.loadedWithoutTag(v.Type()) {
+			g.fields = append(g.fields, f)
+		}
+	}
+}
+
+// collectReport adds the fields of st that envstruct.Report visits, in the
+// same order. Nested structs in exported fields are flattened. Other fields
+// without an `env` tag that might refer to a struct are listed, so that
+// envstruct walks them as it would without the generated method. This keeps
+// the fields of unexported structs read only, as reports expect.
+func (g *generator) collectReport(st *types.Struct, structAccess, path, prefix string) {
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		structTag := reflect.StructTag(st.Tag(i))
+
+		f := reportField{
+			structAccess: structAccess,
+			name:         v.Name(),
+			path:         path + "." + v.Name(),
+			prefix:       prefix,
+		}
+
+		if tags.Split(structTag.Get(tags.Env))[0] != "" {
+			g.reportFields = append(g.reportFields, f)
+			continue
+		}
+
+		if nested, ok := g.flattened(v.Type()); ok && v.Exported() {
+			access := "&" + strings.TrimPrefix(structAccess, "&") + "." + v.Name()
+			g.collectReport(nested, access, f.path, prefix+structTag.Get(tags.EnvPrefix))
+			continue
+		}
+
+		if g.loadedWithoutTag(v.Type()) {
+			g.reportFields = append(g.reportFields, f)
+		}
+	}
+}
+
+// flattened returns the struct of a field without an `env` tag whose fields
+// are collected individually.
+func (g *generator) flattened(t types.Type) (*types.Struct, bool) {
+	if _, ok := t.Underlying().(*types.Pointer); ok || !g.rules.IsNested(t) {
+		return nil, false
+	}
+
+	if named, ok := t.(*types.Named); ok {
+		if p := named.Obj().Pkg(); p != nil && p.Path() == gotypes.EnvstructPath {
+			return nil, false
+		}
+	}
+
+	return gotypes.NestedStruct(t), true
+}
+
+// loadedWithoutTag returns true if Load changes a field of type t without an
+// `env` tag: pointers are allocated, unmarshallers are called and nested
+// structs are loaded.
+func (g *generator) loadedWithoutTag(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Struct:
+		return true
+	}
+
+	return g.rules.IsLeaf(t)
+}
+
+func isInlineKind(b *types.Basic) bool {
+	return b.Info()&(types.IsBoolean|types.IsInteger|types.IsFloat|types.IsString) != 0 &&
+		b.Kind() != types.UnsafePointer && b.Kind() != types.Uintptr
+}
+
+// taggedFields returns the fields envstruct computes the fingerprint of the
+// struct tags from: the path and tag of every field with an `env` or
+// `envprefix` tag, including the fields of nested structs.
+func taggedFields(st *types.Struct, path string, fields []string) []string {
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		structTag := reflect.StructTag(st.Tag(i))
+		fieldPath := path + "." + v.Name()
+
+		if tags.Tagged(structTag) {
+			fields = append(fields, fieldPath+" "+st.Tag(i))
+		}
+
+		if nested, ok := v.Type().Underlying().(*types.Struct); ok && tags.Split(structTag.Get(tags.Env))[0] == "" {
+			fields = taggedFields(nested, fieldPath, fields)
+		}
+	}
+
+	return fields
+}
+
+func (g *generator) write(b *bytes.Buffer, pkgName, typeName string) {
+	var imports []string
+	for _, f := range g.fields {
+		if f.inline() {
+			imports = append(imports, `"os"`, "")
+			break
+		}
+	}
+	imports = append(imports, `envstruct "`+gotypes.EnvstructPath+`"`)
+
+	fmt.Fprintf(b, "// Code generated by envstructgen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(b, "package %s\n\n", pkgName)
+	fmt.Fprintf(b, "import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+
+	fmt.Fprintf(b, "// EnvstructFingerprint returns the fingerprint of the struct tags the\n")
+	fmt.Fprintf(b, "// methods of c were generated for.\n")
+	fmt.Fprintf(b, "func (c *%s) EnvstructFingerprint() string {\n", typeName)
+	fmt.Fprintf(b, "return %q\n", g.fingerprint)
+	fmt.Fprintf(b, "}\n\n")
+
+	g.writeLoadEnv(b, typeName)
+	g.writeToEnv(b, typeName)
+	g.writeReport(b, typeName)
+}
+
+func (g *generator) writeLoadEnv(b *bytes.Buffer, typeName string) {
+	fmt.Fprintf(b, "// EnvstructLoadEnv loads c from the environment. It is used by envstruct.Load.\n")
+	fmt.Fprintf(b, "func (c *%s) EnvstructLoadEnv() error {\n", typeName)
+	fmt.Fprintf(b, "var missing []string\n\n")
+
+	for _, f := range g.fields {
+		if !f.inline() {
+			fmt.Fprintf(b, "if m, err := envstruct.LoadField(&%s, %s, %q); err != nil {\n", f.access, tagLiteral(f.tag), f.prefix)
+			fmt.Fprintf(b, "return err\n")
+			fmt.Fprintf(b, "} else {\n")
+			fmt.Fprintf(b, "missing = append(missing, m...)\n")
+			fmt.Fprintf(b, "}\n\n")
+			continue
+		}
+
+		fmt.Fprintf(b, "if v := os.Getenv(%q); v != \"\" {\n", f.envVar)
+		fmt.Fprintf(b, "parsed, err := envstruct.ParseValue[%s](%q, v)\n", f.basic.Name(), f.envVar)
+		fmt.Fprintf(b, "if err != nil {\n")
+		fmt.Fprintf(b, "return err\n")
+		fmt.Fprintf(b, "}\n")
+		fmt.Fprintf(b, "%s = parsed\n", f.access)
+		if f.required() {
+			fmt.Fprintf(b, "} else {\n")
+			fmt.Fprintf(b, "missing = append(missing, %q)\n", f.envVar)
+		}
+		fmt.Fprintf(b, "}\n\n")
+	}
+
+	fmt.Fprintf(b, "return envstruct.MissingError(missing)\n")
+	fmt.Fprintf(b, "}\n\n")
+}
+
+func (g *generator) writeToEnv(b *bytes.Buffer, typeName string) {
+	fmt.Fprintf(b, "// EnvstructToEnv returns the environment of c. It is used by envstruct.ToEnv.\n")
+	fmt.Fprintf(b, "func (c *%s) EnvstructToEnv() []string {\n", typeName)
+	fmt.Fprintf(b, "var env []string\n")
+
+	for _, f := range g.fields {
+		if !f.inline() {
+			fmt.Fprintf(b, "env = append(env, envstruct.FormatField(&%s, %s, %q)...)\n", f.access, tagLiteral(f.tag), f.prefix)
+			continue
+		}
+
+		fmt.Fprintf(b, "env = append(env, %q+envstruct.FormatValue(%s))\n", f.envVar+"=", f.access)
+	}
+
+	fmt.Fprintf(b, "\nreturn env\n")
+	fmt.Fprintf(b, "}\n\n")
+}
+
+func (g *generator) writeReport(b *bytes.Buffer, typeName string) {
+	fmt.Fprintf(b, "// EnvstructReport returns the fields of c that are reported. It is used by\n")
+	fmt.Fprintf(b, "// envstruct.Report and envstruct.WriteReport.\n")
+	fmt.Fprintf(b, "func (c *%s) EnvstructReport() []envstruct.ReportField {\n", typeName)
+	fmt.Fprintf(b, "return []envstruct.ReportField{\n")
+
+	for _, f := range g.reportFields {
+		fmt.Fprintf(b, "{Struct: %s, Name: %q, Path: %q", f.structAccess, f.name, f.path)
+		if f.prefix != "" {
+			fmt.Fprintf(b, ", Prefix: %q", f.prefix)
+		}
+		fmt.Fprintf(b, "},\n")
+	}
+
+	fmt.Fprintf(b, "}\n")
+	fmt.Fprintf(b, "}\n")
+}
+
+func tagLiteral(tag string) string {
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+
+	return "`" + tag + "`"
+}
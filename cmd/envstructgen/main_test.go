@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const gentest = "../../internal/gentest"
+
+func TestGenerateIsUpToDate(t *testing.T) {
+	pkg, err := loadPackage(gentest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := generate(pkg, "Config")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile(filepath.Join(gentest, "config_envstruct.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("generated code differs from %s/config_envstruct.go, run go generate:\n%s", gentest, got)
+	}
+}
+
+func TestRunWritesOutput(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out.go")
+	if err := run([]string{"-type", "Config", "-output", output, gentest}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(string(got), "// Code generated by envstructgen; DO NOT EDIT.") {
+		t.Errorf("output does not start with the generated code comment:\n%s", got)
+	}
+}
+
+func TestRunUnknownType(t *testing.T) {
+	err := run([]string{"-type", "Unknown", gentest})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
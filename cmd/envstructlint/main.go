@@ -0,0 +1,15 @@
+// Command envstructlint checks the `env` struct tags of structs loaded with
+// envstruct. It can be run on its own or by go vet:
+//
+//	go vet -vettool=$(which envstructlint) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"code.cloudfoundry.org/go-envstruct/envstructlint"
+)
+
+func main() {
+	singlechecker.Main(envstructlint.Analyzer)
+}
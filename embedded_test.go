@@ -0,0 +1,209 @@
+package envstruct_test
+
+import (
+	"bytes"
+	"os"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type EmbeddedServer struct {
+	Host string `env:"HOST,report"`
+	Port int    `env:"PORT,report"`
+}
+
+type EmbeddedLimits struct {
+	MaxConns int `env:"MAX_CONNS,report"`
+}
+
+type embeddedUnexported struct {
+	Region string `env:"EMBEDDED_REGION,report"`
+}
+
+type EmbeddedTestStruct struct {
+	EmbeddedServer
+	*EmbeddedLimits
+	embeddedUnexported
+
+	Name string `env:"EMBEDDED_NAME,report"`
+}
+
+type PrefixedTestStruct struct {
+	EmbeddedServer `envprefix:"API_"`
+
+	Admin  EmbeddedServer  `envprefix:"ADMIN_"`
+	Limits *EmbeddedLimits `envprefix:"DB_"`
+}
+
+type InterfaceTestStruct struct {
+	Server  interface{}
+	Timeout interface{} `env:"INTERFACE_TIMEOUT,report"`
+	Unset   interface{} `env:"INTERFACE_UNSET"`
+}
+
+var embeddedEnvVars = map[string]string{
+	"HOST":              "localhost",
+	"PORT":              "8080",
+	"MAX_CONNS":         "10",
+	"EMBEDDED_REGION":   "eu-west-1",
+	"EMBEDDED_NAME":     "embedded",
+	"API_HOST":          "api.example.com",
+	"API_PORT":          "443",
+	"ADMIN_HOST":        "admin.example.com",
+	"ADMIN_PORT":        "8443",
+	"DB_MAX_CONNS":      "20",
+	"INTERFACE_TIMEOUT": "5",
+}
+
+var _ = Describe("embedded and interface fields", func() {
+	BeforeEach(func() {
+		for k, v := range embeddedEnvVars {
+			os.Setenv(k, v)
+		}
+	})
+
+	AfterEach(func() {
+		for k := range embeddedEnvVars {
+			os.Unsetenv(k)
+		}
+	})
+
+	Describe("embedded structs", func() {
+		var ts EmbeddedTestStruct
+
+		BeforeEach(func() {
+			ts = EmbeddedTestStruct{}
+			Expect(envstruct.Load(&ts)).To(Succeed())
+		})
+
+		It("flattens embedded struct fields", func() {
+			Expect(ts.Host).To(Equal("localhost"))
+			Expect(ts.Port).To(Equal(8080))
+			Expect(ts.Name).To(Equal("embedded"))
+		})
+
+		It("allocates embedded pointers", func() {
+			Expect(ts.EmbeddedLimits).ToNot(BeNil())
+			Expect(ts.MaxConns).To(Equal(10))
+		})
+
+		It("loads fields of unexported embedded structs", func() {
+			Expect(envstruct.ToEnv(&ts)).To(ContainElement("EMBEDDED_REGION=eu-west-1"))
+		})
+
+		It("includes embedded fields in ToEnv", func() {
+			Expect(envstruct.ToEnv(&ts)).To(ConsistOf(
+				"HOST=localhost",
+				"PORT=8080",
+				"MAX_CONNS=10",
+				"EMBEDDED_REGION=eu-west-1",
+				"EMBEDDED_NAME=embedded",
+			))
+		})
+
+		It("reports embedded fields by their path", func() {
+			entries, err := envstruct.Report(&ts)
+			Expect(err).ToNot(HaveOccurred())
+
+			var fields []string
+			for _, e := range entries {
+				fields = append(fields, e.Field)
+			}
+
+			Expect(fields).To(Equal([]string{
+				"EmbeddedTestStruct.EmbeddedServer.Host",
+				"EmbeddedTestStruct.EmbeddedServer.Port",
+				"EmbeddedTestStruct.EmbeddedLimits.MaxConns",
+				"EmbeddedTestStruct.embeddedUnexported.Region",
+				"EmbeddedTestStruct.Name",
+			}))
+		})
+	})
+
+	Describe("envprefix", func() {
+		var ts PrefixedTestStruct
+
+		BeforeEach(func() {
+			ts = PrefixedTestStruct{}
+			Expect(envstruct.Load(&ts)).To(Succeed())
+		})
+
+		It("prefixes the environment variables of nested structs", func() {
+			Expect(ts.EmbeddedServer.Host).To(Equal("api.example.com"))
+			Expect(ts.EmbeddedServer.Port).To(Equal(443))
+			Expect(ts.Admin.Host).To(Equal("admin.example.com"))
+			Expect(ts.Admin.Port).To(Equal(8443))
+			Expect(ts.Limits.MaxConns).To(Equal(20))
+		})
+
+		It("uses the prefixed names in ToEnv", func() {
+			Expect(envstruct.ToEnv(&ts)).To(ConsistOf(
+				"API_HOST=api.example.com",
+				"API_PORT=443",
+				"ADMIN_HOST=admin.example.com",
+				"ADMIN_PORT=8443",
+				"DB_MAX_CONNS=20",
+			))
+		})
+
+		It("uses the prefixed names in the report", func() {
+			var buf bytes.Buffer
+			envstruct.ReportWriter = &buf
+			defer func() { envstruct.ReportWriter = os.Stderr }()
+
+			Expect(envstruct.WriteReport(&ts)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("ADMIN_HOST"))
+			Expect(buf.String()).To(ContainSubstring("DB_MAX_CONNS"))
+		})
+
+		It("uses the prefixed name in parse errors", func() {
+			os.Setenv("ADMIN_PORT", "not-a-port")
+
+			err := envstruct.Load(&PrefixedTestStruct{})
+			Expect(err).To(MatchError(ContainSubstring("ADMIN_PORT")))
+		})
+	})
+
+	Describe("interface fields", func() {
+		It("loads the struct an interface points to", func() {
+			server := &EmbeddedServer{}
+			ts := InterfaceTestStruct{Server: server, Timeout: new(int)}
+
+			Expect(envstruct.Load(&ts)).To(Succeed())
+			Expect(server.Host).To(Equal("localhost"))
+			Expect(server.Port).To(Equal(8080))
+		})
+
+		It("loads the value an interface points to", func() {
+			timeout := 0
+			ts := InterfaceTestStruct{Timeout: &timeout}
+
+			Expect(envstruct.Load(&ts)).To(Succeed())
+			Expect(timeout).To(Equal(5))
+			Expect(envstruct.ToEnv(&ts)).To(ConsistOf("INTERFACE_TIMEOUT=5"))
+		})
+
+		It("leaves nil interfaces alone when the variable is not set", func() {
+			ts := InterfaceTestStruct{Timeout: new(int)}
+
+			Expect(envstruct.Load(&ts)).To(Succeed())
+			Expect(ts.Server).To(BeNil())
+			Expect(ts.Unset).To(BeNil())
+		})
+
+		It("returns an error for a nil interface when the variable is set", func() {
+			ts := InterfaceTestStruct{}
+
+			Expect(envstruct.Load(&ts)).To(MatchError(ContainSubstring("unsupported type interface")))
+		})
+
+		It("returns an error for an interface holding a non-pointer", func() {
+			ts := InterfaceTestStruct{Timeout: 1}
+
+			Expect(envstruct.Load(&ts)).To(MatchError(ContainSubstring("unsupported type interface")))
+		})
+	})
+})
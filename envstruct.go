@@ -1,21 +1,38 @@
 package envstruct
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/netip"
 	"net/url"
 	"os"
 	"reflect"
+	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	"code.cloudfoundry.org/go-envstruct/internal/tags"
 )
 
 const (
 	indexEnvVar = 0
 
-	tagRequired = "required"
-	tagReport   = "report"
+	tagRequired       = tags.Required
+	tagReport         = tags.Report
+	tagMask           = tags.Mask
+	tagHash           = tags.Hash
+	tagRedactUserinfo = tags.RedactUserinfo
+	tagLayout         = tags.Layout
+	tagExtended       = tags.Extended
+	tagEncoding       = tags.Encoding
+	tagOptional       = tags.Optional
+	tagEnum           = tags.Enum
+
+	tagEnvPrefix = tags.EnvPrefix
 )
 
 // Unmarshaller is a type which unmarshals itself from an environment variable.
@@ -24,13 +41,33 @@ type Unmarshaller interface {
 }
 
 // Load will use the `env` tags from a struct to populate the structs values and
-// perform validations.
+// perform validations. An InvalidTargetError is returned if t is not a
+// non-nil pointer to a struct. If t has methods generated by envstructgen,
+// they are used instead. An error is returned if they were generated for
+// different struct tags.
 func Load(t interface{}) error {
+	val, err := targetStruct(t)
+	if err != nil {
+		return err
+	}
+
+	if l, ok := t.(envLoader); ok {
+		if err := checkGenerated(l, val); err != nil {
+			return err
+		}
+
+		return l.EnvstructLoadEnv()
+	}
+
 	missing, err := load(t)
 	if err != nil {
 		return err
 	}
 
+	return missingError(missing)
+}
+
+func missingError(missing []string) error {
 	if len(missing) > 0 {
 		return fmt.Errorf(
 			"missing required environment variables: %s",
@@ -42,25 +79,18 @@ func Load(t interface{}) error {
 }
 
 func load(t interface{}) (missing []string, err error) {
-	val := reflect.ValueOf(t).Elem()
-
-	for i := 0; i < val.NumField(); i++ {
-		valueField := val.Field(i)
-		typeField := val.Type().Field(i)
-		tag := typeField.Tag
-
-		tagProperties := separateOnComma(tag.Get("env"))
-		envVar := tagProperties[indexEnvVar]
-		envVal := os.Getenv(envVar)
-		required := tagPropertiesContains(tagProperties, tagRequired)
+	val, err := targetStruct(t)
+	if err != nil {
+		return nil, err
+	}
 
-		if isInvalid(envVal, required) {
-			missing = append(missing, envVar)
-			continue
-		}
+	return loadStruct(val, "")
+}
 
-		hasEnvTag := envVar != ""
-		subMissing, err := setField(valueField, envVal, hasEnvTag)
+func loadStruct(val reflect.Value, prefix string) (missing []string, err error) {
+	plan := planFor(val.Type(), prefix)
+	for i := range plan.fields {
+		subMissing, err := loadField(val.Field(i), &plan.fields[i])
 		if err != nil {
 			return nil, err
 		}
@@ -68,43 +98,206 @@ func load(t interface{}) (missing []string, err error) {
 		missing = append(missing, subMissing...)
 	}
 
+	if val.CanAddr() && val.Addr().CanInterface() && len(missing) == 0 {
+		if v, ok := val.Addr().Interface().(loadValidator); ok {
+			if err := v.validateLoad(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return missing, nil
+}
+
+// loadField loads a single field of a struct as described by f. Fields
+// without an `env` tag that refer to a struct have the fields of that struct
+// loaded.
+func loadField(valueField reflect.Value, f *fieldPlan) (missing []string, err error) {
+	if !f.hasEnvTag {
+		if isUnsetOptional(valueField, f.optional, f.nestedPrefix) {
+			return nil, nil
+		}
+
+		if nested, ok := nestedStruct(valueField, true); ok {
+			return loadStruct(nested, f.nestedPrefix)
+		}
+	}
+
+	envVal := os.Getenv(f.envVar())
+
+	return loadValue(valueField, envVal, f)
+}
+
+// loadValue sets value from the value of its environment variable as
+// described by f, checking that required values are present.
+func loadValue(value reflect.Value, envVal string, f *fieldPlan) (missing []string, err error) {
+	envVar := f.envVar()
+
+	if isInvalid(envVal, f.required) {
+		return []string{envVar}, nil
+	}
+
+	if f.enum != nil && envVal != "" {
+		if err := checkEnum(f.list, envVal, f.enum); err != nil {
+			return nil, &ParseError{EnvVar: envVar, Type: value.Type(), Err: err}
+		}
+	}
+
+	if f.set != nil && value.CanSet() {
+		if envVal != "" {
+			err = newParseError(value.Type(), f.set(value, envVal, f.tagProperties))
+		}
+	} else {
+		missing, err = setField(value, envVal, f.tagProperties)
+	}
+	if err != nil {
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) && parseErr.EnvVar == "" {
+			parseErr.EnvVar = envVar
+		}
+
+		return nil, err
+	}
+
 	return missing, nil
 }
 
+// enumValues returns the values allowed by the `enum` option of the `env`
+// struct tag, separated by `|` as in `enum=debug|info|warn`.
+func enumValues(tagProperties []string) ([]string, bool) {
+	values, ok := tagPropertyValue(tagProperties, tagEnum)
+	if !ok {
+		return nil, false
+	}
+
+	return strings.Split(values, "|"), true
+}
+
+// checkEnum returns an error if input is not one of values or, for lists,
+// if one of its comma separated elements is not.
+func checkEnum(list bool, input string, values []string) error {
+	inputs := []string{input}
+	if list {
+		inputs = separateOnComma(input)
+	}
+
+	for _, v := range inputs {
+		if !slices.Contains(values, v) {
+			return fmt.Errorf("must be one of %s", strings.Join(values, ", "))
+		}
+	}
+
+	return nil
+}
+
+// isList returns true if a field of type t is loaded from a comma separated
+// list of elements.
+func isList(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && !hasTypeHandling(t)
+}
+
+// loadValidator is implemented by structs provided by this package that
+// need to validate their fields once they have been loaded.
+type loadValidator interface {
+	validateLoad() error
+}
+
 // ToEnv will return a slice of strings that can be used with exec.Cmd.Env
-// formatted as `ENVAR_NAME=value` for a given struct.
+// formatted as `ENVAR_NAME=value` for a given struct. The values of Secret
+// fields are included. If t is not a non-nil pointer to a struct, nil is
+// returned; use FormatEnv to get an error instead. If t has methods generated
+// by envstructgen for its current struct tags, they are used instead.
 func ToEnv(t interface{}) []string {
-	val := reflect.ValueOf(t).Elem()
+	env, _ := FormatEnv(t)
+	return env
+}
 
+// FormatEnv returns the same environment as ToEnv, but returns an
+// InvalidTargetError if t is not a non-nil pointer to a struct.
+func FormatEnv(t interface{}) ([]string, error) {
+	val, err := targetStruct(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if f, ok := t.(envFormatter); ok && checkGenerated(f, val) == nil {
+		return f.EnvstructToEnv(), nil
+	}
+
+	return toEnv(val, ""), nil
+}
+
+func toEnv(val reflect.Value, prefix string) []string {
 	var results []string
-	for i := 0; i < val.NumField(); i++ {
-		valueField := val.Field(i)
-		typeField := val.Type().Field(i)
-		tag := typeField.Tag
-
-		tagProperties := separateOnComma(tag.Get("env"))
-		envVar := tagProperties[indexEnvVar]
-
-		switch valueField.Kind() {
-		case reflect.Slice:
-			results = append(results, formatSlice(envVar, valueField))
-		case reflect.Map:
-			results = append(results, formatMap(envVar, valueField))
-		case reflect.Struct:
-			results = append(results, ToEnv(valueField.Addr().Interface())...)
-		case reflect.Pointer:
-			if valueField.Type() == reflect.TypeOf(&url.URL{}) {
-				results = append(results, fmt.Sprintf("%s=%+v", envVar, valueField))
-				continue
-			}
+	plan := planFor(val.Type(), prefix)
+	for i := range plan.fields {
+		results = append(results, fieldEnv(val.Field(i), &plan.fields[i], prefix)...)
+	}
 
-			results = append(results, ToEnv(valueField.Interface())...)
-		default:
-			results = append(results, fmt.Sprintf("%s=%+v", envVar, valueField))
+	return results
+}
+
+// fieldEnv returns the environment of a single field of a struct as
+// described by f, as returned by ToEnv.
+func fieldEnv(valueField reflect.Value, f *fieldPlan, prefix string) []string {
+	if !f.hasEnvTag {
+		if nested, ok := nestedStruct(valueField, false); ok {
+			return toEnv(nested, f.nestedPrefix)
 		}
+
+		return nil
 	}
 
-	return results
+	if f.format != nil {
+		if isNilPointer(valueField) {
+			return nil
+		}
+
+		return []string{f.envVar() + "=" + f.format(valueField, f.tagProperties)}
+	}
+
+	return formatEnv(valueField, f.tagProperties, prefix)
+}
+
+func formatEnv(value reflect.Value, tagProperties []string, prefix string) []string {
+	envVar := tagProperties[indexEnvVar]
+
+	if value.CanInterface() {
+		if r, ok := value.Interface().(revealer); ok {
+			value = r.reveal()
+		}
+	}
+
+	if isNilPointer(value) {
+		return nil
+	}
+
+	// The certificates of a pool can not be retrieved, so it is left out
+	// rather than written as an empty value that would load an empty pool.
+	if value.Type() == certPoolType {
+		return nil
+	}
+
+	if hasTypeHandling(value.Type()) {
+		return []string{fmt.Sprintf("%s=%s", envVar, formatValue(value, tagProperties))}
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		return []string{formatSlice(envVar, value, tagProperties)}
+	case reflect.Map:
+		return []string{formatMap(envVar, value)}
+	case reflect.Struct:
+		return toEnv(value, prefix)
+	case reflect.Pointer, reflect.Interface:
+		return formatEnv(value.Elem(), tagProperties, prefix)
+	}
+
+	return []string{fmt.Sprintf("%s=%+v", envVar, value)}
 }
 
 func tagPropertiesContains(properties []string, match string) bool {
@@ -117,6 +310,21 @@ func tagPropertiesContains(properties []string, match string) bool {
 	return false
 }
 
+func tagPropertyValue(properties []string, key string) (string, bool) {
+	for _, v := range properties {
+		if k, value, ok := strings.Cut(v, "="); ok && k == key {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+func tagPropertiesHasKey(properties []string, key string) bool {
+	_, ok := tagPropertyValue(properties, key)
+	return ok
+}
+
 func unmarshaller(v reflect.Value) (Unmarshaller, bool) {
 	if unmarshaller, ok := v.Interface().(Unmarshaller); ok {
 		return unmarshaller, ok
@@ -127,31 +335,57 @@ func unmarshaller(v reflect.Value) (Unmarshaller, bool) {
 	return nil, false
 }
 
-func setField(value reflect.Value, input string, hasEnvTag bool) (missing []string, err error) {
+func setField(value reflect.Value, input string, tagProperties []string) (missing []string, err error) {
 	if !value.CanSet() {
 		return nil, nil
 	}
 
+	if input == "" &&
+		value.Kind() == reflect.Pointer &&
+		value.IsNil() &&
+		tagPropertiesContains(tagProperties, tagOptional) {
+
+		return nil, nil
+	}
+
 	if input == "" &&
 		(value.Kind() != reflect.Pointer) &&
-		(value.Kind() != reflect.Struct) {
+		(value.Kind() != reflect.Struct) &&
+		(value.Kind() != reflect.Interface) {
 
 		return nil, nil
 	}
 
+	// Nil pointers to unmarshallers are only allocated if there is a value
+	// to unmarshal, so that they stay nil when the variable is not set.
+	if value.Kind() == reflect.Pointer && value.IsNil() && value.Type().Implements(unmarshallerType) {
+		if input == "" {
+			return nil, nil
+		}
+
+		value.Set(reflect.New(value.Type().Elem()))
+	}
+
+	if value.CanAddr() && value.Addr().CanInterface() {
+		if u, ok := value.Addr().Interface().(tagUnmarshaller); ok {
+			return nil, u.unmarshalEnvWithTags(input, tagProperties)
+		}
+	}
+
 	if unmarshaller, ok := unmarshaller(value); ok {
 		return nil, unmarshaller.UnmarshalEnv(input)
-	} else {
-		if value.Kind() == reflect.Struct && hasEnvTag {
-			return nil, fmt.Errorf("nested struct %s with env tag needs to have an UnmarshallEnv method", value.Type().Name())
+	}
+
+	if set, ok := lookupSetter(value.Type()); ok {
+		if input == "" {
+			return nil, nil
 		}
+
+		return nil, newParseError(value.Type(), set(value, input, tagProperties))
 	}
 
-	switch value.Type() {
-	case reflect.TypeOf(time.Second):
-		return nil, setDuration(value, input)
-	case reflect.TypeOf(&url.URL{}):
-		return nil, setURL(value, input)
+	if value.Kind() == reflect.Struct && hasEnvTag(tagProperties) {
+		return nil, nestedStructError(value.Type())
 	}
 
 	switch value.Kind() {
@@ -160,26 +394,260 @@ func setField(value reflect.Value, input string, hasEnvTag bool) (missing []stri
 	case reflect.Bool:
 		return nil, setBool(value, input)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return nil, setInt(value, input)
+		return nil, newParseError(value.Type(), setInt(value, input))
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return nil, setUint(value, input)
+		return nil, newParseError(value.Type(), setUint(value, input))
 	case reflect.Float32, reflect.Float64:
-		return nil, setFloat(value, input)
+		return nil, newParseError(value.Type(), setFloat(value, input))
 	case reflect.Complex64, reflect.Complex128:
-		return nil, setComplex(value, input)
+		return nil, newParseError(value.Type(), setComplex(value, input))
 	case reflect.Slice:
-		return nil, setSlice(value, input, hasEnvTag)
+		return nil, setSlice(value, input, tagProperties)
+	case reflect.Array:
+		return nil, newParseError(value.Type(), setArray(value, input, tagProperties))
 	case reflect.Map:
 		return nil, setMap(value, input)
 	case reflect.Struct:
 		return setStruct(value)
 	case reflect.Pointer:
-		return setPointerToStruct(value, input, hasEnvTag)
+		return setPointerToStruct(value, input, tagProperties)
+	case reflect.Interface:
+		return setInterface(value, input, tagProperties)
 	}
 
 	return nil, fmt.Errorf("unsupported type %s", value.Kind())
 }
 
+type (
+	typeSetter    func(value reflect.Value, input string, tagProperties []string) error
+	typeFormatter func(value reflect.Value, tagProperties []string) string
+)
+
+// typeSetters parse types that are handled specially regardless of their
+// kind.
+var typeSetters = map[reflect.Type]typeSetter{
+	reflect.TypeOf(time.Second):        setDuration,
+	reflect.TypeOf(time.Time{}):        setTime,
+	reflect.TypeOf(&time.Location{}):   ignoringOptions(setLocation),
+	reflect.TypeOf(&url.URL{}):         ignoringOptions(setURL),
+	reflect.TypeOf(net.IP{}):           ignoringOptions(setIP),
+	reflect.TypeOf(net.IPNet{}):        ignoringOptions(setIPNet),
+	reflect.TypeOf(&net.IPNet{}):       ignoringOptions(setIPNetPointer),
+	reflect.TypeOf(net.HardwareAddr{}): ignoringOptions(setHardwareAddr),
+	reflect.TypeOf(netip.Addr{}):       ignoringOptions(setAddr),
+	reflect.TypeOf(netip.AddrPort{}):   ignoringOptions(setAddrPort),
+	reflect.TypeOf(netip.Prefix{}):     ignoringOptions(setPrefix),
+	certificateType:                    ignoringOptions(setCertificate),
+	certificateSliceType:               ignoringOptions(setCertificates),
+	certPoolType:                       ignoringOptions(setCertPool),
+	privateKeyType:                     ignoringOptions(setPrivateKey),
+	reflect.TypeOf(&regexp.Regexp{}):   ignoringOptions(setRegexp),
+	reflect.TypeOf(Glob("")):           ignoringOptions(setGlob),
+	reflect.TypeOf(os.FileMode(0)):     ignoringOptions(setFileMode),
+}
+
+// typeFormatters format types whose default formatting can not be parsed
+// back by their setter.
+var typeFormatters = map[reflect.Type]typeFormatter{
+	reflect.TypeOf(time.Time{}):        formatTime,
+	reflect.TypeOf(&time.Location{}):   formatStringer,
+	reflect.TypeOf(&url.URL{}):         formatStringer,
+	reflect.TypeOf(net.IP{}):           formatStringer,
+	reflect.TypeOf(net.IPNet{}):        formatIPNet,
+	reflect.TypeOf(&net.IPNet{}):       formatStringer,
+	reflect.TypeOf(net.HardwareAddr{}): formatStringer,
+	reflect.TypeOf(netip.Addr{}):       formatStringer,
+	reflect.TypeOf(netip.AddrPort{}):   formatStringer,
+	reflect.TypeOf(netip.Prefix{}):     formatStringer,
+	reflect.TypeOf(ByteSize(0)):        formatByteSize,
+	reflect.TypeOf(os.FileMode(0)):     formatFileMode,
+	certificateType:                    formatCertificate,
+	certificateSliceType:               formatCertificates,
+	certPoolType:                       formatCertPool,
+	privateKeyType:                     formatPrivateKey,
+	reflect.TypeOf(&regexp.Regexp{}):   formatStringer,
+}
+
+// typeSummaries describe types that hold key material or that are too large
+// to display. Reports always display the summary instead of the value.
+var typeSummaries = map[reflect.Type]func(reflect.Value) string{
+	certificateType:      summarizeCertificate,
+	certificateSliceType: summarizeCertificates,
+	certPoolType:         summarizeCertPool,
+	privateKeyType:       summarizePrivateKey,
+}
+
+func lookupSetter(t reflect.Type) (typeSetter, bool) {
+	if set, ok := registeredSetter(t); ok {
+		return set, true
+	}
+
+	if set, ok := typeSetters[t]; ok {
+		return set, true
+	}
+
+	if isBytes(t) {
+		return setBytes, true
+	}
+
+	return nil, false
+}
+
+func lookupFormatter(t reflect.Type) (typeFormatter, bool) {
+	if format, ok := registeredFormatter(t); ok {
+		return format, true
+	}
+
+	if format, ok := typeFormatters[t]; ok {
+		return format, true
+	}
+
+	if isBytes(t) {
+		return formatBytes, true
+	}
+
+	return nil, false
+}
+
+// hasTypeHandling returns true if t is parsed or formatted as a whole rather
+// than according to its kind.
+func hasTypeHandling(t reflect.Type) bool {
+	if _, ok := lookupFormatter(t); ok {
+		return true
+	}
+
+	_, ok := lookupSetter(t)
+	return ok
+}
+
+// ignoringOptions adapts a setter that does not support any tag options.
+func ignoringOptions(set func(reflect.Value, string) error) typeSetter {
+	return func(value reflect.Value, input string, _ []string) error {
+		return set(value, input)
+	}
+}
+
+func hasEnvTag(tagProperties []string) bool {
+	return len(tagProperties) > 0 && tagProperties[indexEnvVar] != ""
+}
+
+// envTagProperties returns the properties of the `env` tag with prefix added
+// to the environment variable.
+func envTagProperties(tag reflect.StructTag, prefix string) []string {
+	tagProperties := tags.Split(tag.Get(tags.Env))
+	if tagProperties[indexEnvVar] != "" {
+		tagProperties[indexEnvVar] = prefix + tagProperties[indexEnvVar]
+	}
+
+	return tagProperties
+}
+
+// nestedStruct returns the struct that a field without an `env` tag refers
+// to, if its fields should be loaded individually. This is the case for
+// structs, embedded or not, pointers to structs and interfaces holding a
+// pointer to a struct. Nil pointers are allocated if allocate is true.
+func nestedStruct(value reflect.Value, allocate bool) (reflect.Value, bool) {
+	switch value.Kind() {
+	case reflect.Struct:
+		if isLeafType(value.Type()) {
+			return reflect.Value{}, false
+		}
+
+		return value, true
+	case reflect.Pointer:
+		if value.Type().Elem().Kind() != reflect.Struct || isLeafType(value.Type()) {
+			return reflect.Value{}, false
+		}
+
+		if value.IsNil() {
+			if !allocate || !value.CanSet() {
+				return reflect.Value{}, false
+			}
+
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+
+		return value.Elem(), true
+	case reflect.Interface:
+		if value.IsNil() || value.Elem().Kind() != reflect.Pointer {
+			return reflect.Value{}, false
+		}
+
+		return nestedStruct(value.Elem(), false)
+	}
+
+	return reflect.Value{}, false
+}
+
+func isNilPointer(value reflect.Value) bool {
+	return (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) && value.IsNil()
+}
+
+// isUnsetOptional returns true for a nil pointer to a struct tagged
+// `optional` when none of the environment variables of the struct are set.
+// These pointers are left nil instead of being allocated.
+func isUnsetOptional(value reflect.Value, optional bool, prefix string) bool {
+	if !optional ||
+		value.Kind() != reflect.Pointer ||
+		!value.IsNil() ||
+		value.Type().Elem().Kind() != reflect.Struct {
+
+		return false
+	}
+
+	return !envVarsSet(value.Type().Elem(), prefix, map[reflect.Type]bool{})
+}
+
+// envVarsSet returns true if any environment variable read by the fields of
+// the struct type t, including those of nested structs, is set.
+func envVarsSet(t reflect.Type, prefix string, visiting map[reflect.Type]bool) bool {
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	plan := planFor(t, prefix)
+	for i := range plan.fields {
+		f := &plan.fields[i]
+
+		if f.hasEnvTag {
+			if os.Getenv(f.envVar()) != "" {
+				return true
+			}
+
+			continue
+		}
+
+		if f.nestedType != nil && envVarsSet(f.nestedType, f.nestedPrefix, visiting) {
+			return true
+		}
+	}
+
+	return false
+}
+
+var unmarshallerType = reflect.TypeOf((*Unmarshaller)(nil)).Elem()
+
+// isLeafType returns true for types that are loaded from a single
+// environment variable rather than field by field.
+func isLeafType(t reflect.Type) bool {
+	if isUnmarshaller(t) {
+		return true
+	}
+
+	if t.Kind() == reflect.Pointer && t.Elem().Implements(unmarshallerType) {
+		return true
+	}
+
+	return hasTypeHandling(t)
+}
+
+// isUnmarshaller returns true if t or *t implements Unmarshaller.
+func isUnmarshaller(t reflect.Type) bool {
+	return t.Implements(unmarshallerType) || reflect.PointerTo(t).Implements(unmarshallerType)
+}
+
 func separateOnComma(input string) []string {
 	inputs := strings.Split(input, ",")
 
@@ -190,36 +658,49 @@ func separateOnComma(input string) []string {
 	return inputs
 }
 
+// separateElements splits the input of a slice or array of type t into the
+// inputs of its elements.
+func separateElements(t reflect.Type, input string) []string {
+	if t.Elem() == regexpType {
+		return separatePatterns(input)
+	}
+
+	return separateOnComma(input)
+}
+
 func isInvalid(input string, required bool) bool {
 	return required && input == ""
 }
 
 func setStruct(value reflect.Value) (missing []string, err error) {
-	return load(value.Addr().Interface())
+	return loadStruct(value, "")
 }
 
-func setPointerToStruct(value reflect.Value, input string, hasEnvTag bool) (missing []string, err error) {
+func setPointerToStruct(value reflect.Value, input string, tagProperties []string) (missing []string, err error) {
 	if value.IsNil() {
 		p := reflect.New(value.Type().Elem())
 		value.Set(p)
 	}
 
 	if value.Type().Elem().Kind() == reflect.Struct {
-		return load(value.Interface())
+		return loadStruct(value.Elem(), "")
 	}
 
-	return setField(value.Elem(), input, hasEnvTag)
+	return setField(value.Elem(), input, tagProperties)
 }
 
-func setDuration(value reflect.Value, input string) error {
-	d, err := time.ParseDuration(input)
-	if err != nil {
-		return err
-	}
+// setInterface loads the value an interface points to. Interfaces that are
+// nil or do not hold a pointer can not be loaded.
+func setInterface(value reflect.Value, input string, tagProperties []string) (missing []string, err error) {
+	if value.IsNil() || value.Elem().Kind() != reflect.Pointer || value.Elem().IsNil() {
+		if input == "" {
+			return nil, nil
+		}
 
-	value.Set(reflect.ValueOf(d))
+		return nil, fmt.Errorf("unsupported type %s", value.Kind())
+	}
 
-	return nil
+	return setField(value.Elem().Elem(), input, tagProperties)
 }
 
 func setURL(value reflect.Value, input string) error {
@@ -240,29 +721,82 @@ func setString(value reflect.Value, input string) error {
 }
 
 func setBool(value reflect.Value, input string) error {
-	value.SetBool(input == "true" || input == "1")
+	value.SetBool(parseBool(input))
 
 	return nil
 }
 
+func parseBool(input string) bool {
+	return input == "true" || input == "1"
+}
+
 func setInt(value reflect.Value, input string) error {
-	n, err := strconv.ParseInt(input, 10, 64)
+	n, err := parseInt(input, value.Type().Bits())
 	if err != nil {
 		return err
 	}
 
-	value.SetInt(int64(n))
+	value.SetInt(n)
 
 	return nil
 }
 
+func parseInt(input string, bits int) (int64, error) {
+	return strconv.ParseInt(withoutLeadingZeros(input), 0, bits)
+}
+
 func setUint(value reflect.Value, input string) error {
-	n, err := strconv.ParseUint(input, 10, 64)
+	n, err := parseUint(input, value.Type().Bits())
 	if err != nil {
 		return err
 	}
 
-	value.SetUint(uint64(n))
+	value.SetUint(n)
+
+	return nil
+}
+
+func parseUint(input string, bits int) (uint64, error) {
+	return strconv.ParseUint(withoutLeadingZeros(input), 0, bits)
+}
+
+// withoutLeadingZeros removes the leading zeros of an integer without a
+// `0x`, `0o` or `0b` prefix, so that it is parsed as a decimal rather than
+// an octal number, e.g. `08080` as 8080.
+func withoutLeadingZeros(input string) string {
+	sign, digits := "", input
+	if digits != "" && (digits[0] == '-' || digits[0] == '+') {
+		sign, digits = digits[:1], digits[1:]
+	}
+
+	if len(digits) > 1 && digits[0] == '0' && strings.ContainsRune("xXoObB", rune(digits[1])) {
+		return input
+	}
+
+	trimmed := strings.TrimLeft(digits, "0")
+	if trimmed == "" && digits != "" {
+		trimmed = "0"
+	}
+
+	return sign + trimmed
+}
+
+// setFileMode parses a file mode. Unlike other integers, a leading zero
+// makes it octal, as in `0755`.
+// setFileMode parses permission digits such as `755` in octal, as chmod
+// does, unless the input has a `0x`, `0o` or `0b` base prefix.
+func setFileMode(value reflect.Value, input string) error {
+	base := 8
+	if len(input) > 2 && input[0] == '0' && strings.ContainsRune("xXoObB", rune(input[1])) {
+		base = 0
+	}
+
+	n, err := strconv.ParseUint(input, base, 32)
+	if err != nil {
+		return err
+	}
+
+	value.SetUint(n)
 
 	return nil
 }
@@ -295,12 +829,12 @@ func setComplex(value reflect.Value, input string) error {
 	return nil
 }
 
-func setSlice(value reflect.Value, input string, hasEnvTag bool) error {
-	inputs := separateOnComma(input)
+func setSlice(value reflect.Value, input string, tagProperties []string) error {
+	inputs := separateElements(value.Type(), input)
 
 	rs := reflect.MakeSlice(value.Type(), len(inputs), len(inputs))
 	for i, val := range inputs {
-		_, err := setField(rs.Index(i), val, hasEnvTag)
+		_, err := setField(rs.Index(i), val, tagProperties)
 		if err != nil {
 			return err
 		}
@@ -311,6 +845,25 @@ func setSlice(value reflect.Value, input string, hasEnvTag bool) error {
 	return nil
 }
 
+func setArray(value reflect.Value, input string, tagProperties []string) error {
+	inputs := separateElements(value.Type(), input)
+	if len(inputs) != value.Len() {
+		return fmt.Errorf("expects %d values, found %d", value.Len(), len(inputs))
+	}
+
+	ra := reflect.New(value.Type()).Elem()
+	for i, val := range inputs {
+		_, err := setField(ra.Index(i), val, tagProperties)
+		if err != nil {
+			return err
+		}
+	}
+
+	value.Set(ra)
+
+	return nil
+}
+
 func setMap(value reflect.Value, input string) error {
 	inputs := separateOnComma(input)
 
@@ -329,11 +882,11 @@ func setMap(value reflect.Value, input string) error {
 		castedKey := reflect.New(value.Type().Key()).Elem()
 		castedValue := reflect.New(value.Type().Elem()).Elem()
 
-		_, err := setField(castedKey, kv[0], false)
+		_, err := setField(castedKey, kv[0], nil)
 		if err != nil {
 			return fmt.Errorf("setMap: %w", err)
 		}
-		_, err = setField(castedValue, kv[1], false)
+		_, err = setField(castedValue, kv[1], nil)
 		if err != nil {
 			return fmt.Errorf("setMap: %w", err)
 		}
@@ -346,10 +899,10 @@ func setMap(value reflect.Value, input string) error {
 	return nil
 }
 
-func formatSlice(envVar string, value reflect.Value) string {
+func formatSlice(envVar string, value reflect.Value, tagProperties []string) string {
 	var parts []string
 	for i := 0; i < value.Len(); i++ {
-		parts = append(parts, fmt.Sprintf("%+v", value.Index(i)))
+		parts = append(parts, formatValue(value.Index(i), tagProperties))
 	}
 
 	return fmt.Sprintf("%s=%+v", envVar, strings.Join(parts, ","))
@@ -368,6 +921,26 @@ func formatMap(envVar string, value reflect.Value) string {
 	return fmt.Sprintf("%s=%+v", envVar, strings.Join(parts, ","))
 }
 
+func formatValue(value reflect.Value, tagProperties []string) string {
+	if format, ok := lookupFormatter(value.Type()); ok {
+		return format(value, tagProperties)
+	}
+
+	return formatDefault(value, tagProperties)
+}
+
+func formatDefault(value reflect.Value, _ []string) string {
+	return fmt.Sprintf("%+v", value)
+}
+
+func formatStringer(value reflect.Value, _ []string) string {
+	return fmt.Sprint(value)
+}
+
+func formatFileMode(value reflect.Value, _ []string) string {
+	return fmt.Sprintf("0%o", value.Uint())
+}
+
 func uniqueStrings(s []string) []string {
 	m := make(map[string]bool)
 	for _, str := range s {
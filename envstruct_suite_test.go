@@ -3,6 +3,7 @@ package envstruct_test
 import (
 	"crypto/tls"
 	"net/url"
+	"os"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -27,6 +28,7 @@ var (
 		"UINT16_THING":            "2000",
 		"UINT32_THING":            "200000",
 		"UINT64_THING":            "200000000",
+		"FILE_MODE_THING":         "0755",
 		"FLOAT_THING":             "3.14159",
 		"FLOAT32_THING":           "1.2345",
 		"FLOAT64_THING":           "9.8765",
@@ -39,6 +41,7 @@ var (
 		"POINTER_TO_UINT":         "20",
 		"STRING_SLICE_THING":      "one,two,three",
 		"INT_SLICE_THING":         "1,2,3",
+		"STRING_ARRAY_THING":      "one,two,three",
 		"MAP_STRING_STRING_THING": "key_one:value_one,key_two:value_two:with_colon",
 		"MAP_INT_STRING_THING":    "1:value_one,2:value_two:with_colon",
 		"DURATION_THING":          "2s",
@@ -72,6 +75,8 @@ type LargeTestStruct struct {
 	Uint32Thing uint32 `env:"UINT32_THING"`
 	Uint64Thing uint64 `env:"UINT64_THING"`
 
+	FileModeThing os.FileMode `env:"FILE_MODE_THING"`
+
 	Float32Thing float32 `env:"FLOAT32_THING"`
 	Float64Thing float64 `env:"FLOAT64_THING"`
 
@@ -86,6 +91,8 @@ type LargeTestStruct struct {
 	StringSliceThing []string `env:"STRING_SLICE_THING"`
 	IntSliceThing    []int    `env:"INT_SLICE_THING"`
 
+	StringArrayThing [3]string `env:"STRING_ARRAY_THING"`
+
 	MapStringStringThing map[string]string `env:"MAP_STRING_STRING_THING"`
 	MapIntStringThing    map[int]string    `env:"MAP_INT_STRING_THING"`
 
@@ -135,6 +142,7 @@ type ToEnvTestStruct struct {
 	IntThing           int      `env:"INT_THING"`
 	URLThing           *url.URL `env:"URL_THING"`
 	StringSliceThing   []string `env:"STRING_SLICE_THING"`
+	IntArrayThing      [2]int   `env:"INT_ARRAY_THING"`
 	CaseSensitiveThing string   `env:"CaSe_SeNsItIvE_ThInG"`
 	SubStruct          SubTestStruct
 	SubPointerStruct   *SubTestStruct
@@ -133,6 +133,44 @@ var _ = Describe("envstruct", func() {
 				It("populates the int 64 thing", func() {
 					Expect(ts.Int64Thing).To(Equal(int64(200000000)))
 				})
+
+				Context("with a base prefix", func() {
+					BeforeEach(func() {
+						envVars["INT_THING"] = "0x1F"
+						envVars["INT8_THING"] = "-0b101"
+						envVars["INT16_THING"] = "0o17"
+					})
+
+					It("parses the value in the given base", func() {
+						Expect(ts.IntThing).To(Equal(31))
+						Expect(ts.Int8Thing).To(Equal(int8(-5)))
+						Expect(ts.Int16Thing).To(Equal(int16(15)))
+					})
+				})
+
+				Context("with leading zeros", func() {
+					BeforeEach(func() {
+						envVars["INT_THING"] = "08080"
+						envVars["INT8_THING"] = "-0100"
+						envVars["INT16_THING"] = "000"
+					})
+
+					It("parses the value as a decimal", func() {
+						Expect(ts.IntThing).To(Equal(8080))
+						Expect(ts.Int8Thing).To(Equal(int8(-100)))
+						Expect(ts.Int16Thing).To(Equal(int16(0)))
+					})
+				})
+
+				Context("with underscores", func() {
+					BeforeEach(func() {
+						envVars["INT64_THING"] = "1_000_000"
+					})
+
+					It("ignores the underscores", func() {
+						Expect(ts.Int64Thing).To(Equal(int64(1000000)))
+					})
+				})
 			})
 
 			Context("with uints", func() {
@@ -155,6 +193,52 @@ var _ = Describe("envstruct", func() {
 				It("populates the uint 64 thing", func() {
 					Expect(ts.Uint64Thing).To(Equal(uint64(200000000)))
 				})
+
+				It("populates the file mode thing from an octal value", func() {
+					Expect(ts.FileModeThing).To(Equal(os.FileMode(0755)))
+				})
+
+				Context("with a file mode without a leading zero", func() {
+					BeforeEach(func() {
+						envVars["FILE_MODE_THING"] = "755"
+					})
+
+					It("parses the file mode in octal", func() {
+						Expect(ts.FileModeThing).To(Equal(os.FileMode(0755)))
+					})
+				})
+
+				Context("with a file mode with a base prefix", func() {
+					BeforeEach(func() {
+						envVars["FILE_MODE_THING"] = "0o640"
+					})
+
+					It("parses the value in the given base", func() {
+						Expect(ts.FileModeThing).To(Equal(os.FileMode(0640)))
+					})
+				})
+
+				Context("with a base prefix", func() {
+					BeforeEach(func() {
+						envVars["UINT_THING"] = "0xff"
+					})
+
+					It("parses the value in the given base", func() {
+						Expect(ts.UintThing).To(Equal(uint(255)))
+					})
+				})
+
+				Context("with leading zeros", func() {
+					BeforeEach(func() {
+						envVars["UINT_THING"] = "0100"
+						envVars["UINT16_THING"] = "08080"
+					})
+
+					It("parses the value as a decimal", func() {
+						Expect(ts.UintThing).To(Equal(uint(100)))
+						Expect(ts.Uint16Thing).To(Equal(uint16(8080)))
+					})
+				})
 			})
 
 			Context("with floats", func() {
@@ -217,6 +301,35 @@ var _ = Describe("envstruct", func() {
 						Expect(ts.IntSliceThing).To(Equal([]int{1, 2, 3}))
 					})
 				})
+
+				Context("array of strings", func() {
+					It("populates an array of strings", func() {
+						Expect(ts.StringArrayThing).To(Equal([3]string{"one", "two", "three"}))
+					})
+
+					Context("with too few values", func() {
+						BeforeEach(func() {
+							envVars["STRING_ARRAY_THING"] = "one,two"
+						})
+
+						It("returns a parse error naming the environment variable", func() {
+							var parseErr *envstruct.ParseError
+							Expect(errors.As(loadError, &parseErr)).To(BeTrue())
+							Expect(parseErr.EnvVar).To(Equal("STRING_ARRAY_THING"))
+							Expect(loadError).To(MatchError("failed to parse STRING_ARRAY_THING as [3]string: expects 3 values, found 2"))
+						})
+					})
+
+					Context("with too many values", func() {
+						BeforeEach(func() {
+							envVars["STRING_ARRAY_THING"] = "one,two,three,four"
+						})
+
+						It("returns an error", func() {
+							Expect(loadError).To(MatchError("failed to parse STRING_ARRAY_THING as [3]string: expects 3 values, found 4"))
+						})
+					})
+				})
 			})
 
 			Context("with map[string]string", func() {
@@ -371,6 +484,41 @@ var _ = Describe("envstruct", func() {
 				})
 			})
 
+			Context("with an int that overflows its field", func() {
+				BeforeEach(func() {
+					envVars["INT8_THING"] = "200"
+				})
+
+				It("returns a parse error naming the environment variable", func() {
+					err := envstruct.Load(&ts)
+
+					var parseErr *envstruct.ParseError
+					Expect(errors.As(err, &parseErr)).To(BeTrue())
+					Expect(parseErr.EnvVar).To(Equal("INT8_THING"))
+					Expect(err).To(MatchError("failed to parse INT8_THING as int8: value out of range"))
+				})
+			})
+
+			Context("with a uint that overflows its field", func() {
+				BeforeEach(func() {
+					envVars["UINT16_THING"] = "0x10000"
+				})
+
+				It("returns a parse error naming the environment variable", func() {
+					Expect(envstruct.Load(&ts)).To(MatchError("failed to parse UINT16_THING as uint16: value out of range"))
+				})
+			})
+
+			Context("with a negative uint", func() {
+				BeforeEach(func() {
+					envVars["UINT8_THING"] = "-1"
+				})
+
+				It("returns a parse error", func() {
+					Expect(envstruct.Load(&ts)).To(MatchError("failed to parse UINT8_THING as uint8: invalid syntax"))
+				})
+			})
+
 			Context("with a failing unmarshaller pointer", func() {
 				BeforeEach(func() {
 					ts.UnmarshallerPointer.UnmarshalEnvOutput = errors.New("failed to unmarshal")
@@ -428,6 +576,7 @@ var _ = Describe("envstruct", func() {
 				IntThing:           200,
 				URLThing:           url,
 				StringSliceThing:   []string{"thing-1", "thing-2", "thing-3"},
+				IntArrayThing:      [2]int{1, 2},
 				CaseSensitiveThing: "case-sensitive-thing",
 				SubStruct: SubTestStruct{
 					SubThingA: "sub-string-a",
@@ -451,6 +600,7 @@ var _ = Describe("envstruct", func() {
 				"INT_THING=200",
 				"URL_THING=https://example.com",
 				"STRING_SLICE_THING=thing-1,thing-2,thing-3",
+				"INT_ARRAY_THING=1,2",
 				"CaSe_SeNsItIvE_ThInG=case-sensitive-thing",
 				"SUB_THING_A=sub-string-a",
 				"SUB_THING_B=300",
@@ -459,6 +609,27 @@ var _ = Describe("envstruct", func() {
 			))
 		})
 
+		It("returns the same environment from FormatEnv", func() {
+			ts := ToEnvTestStruct{StringThing: "string-thing", IntThing: 200}
+
+			env, err := envstruct.FormatEnv(&ts)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(env).To(Equal(envstruct.ToEnv(&ts)))
+		})
+
+		Context("with a file mode", func() {
+			It("formats the file mode in octal", func() {
+				ts := struct {
+					FileModeThing os.FileMode `env:"FILE_MODE_THING"`
+				}{
+					FileModeThing: 0640,
+				}
+
+				Expect(envstruct.ToEnv(&ts)).To(ConsistOf("FILE_MODE_THING=0640"))
+			})
+		})
+
 		Context("with a map", func() {
 			It("returns a slice with a formatted map for environment variable", func() {
 				ts := ToEnvMapTestStruct{
@@ -0,0 +1,196 @@
+// Package envstructlint defines an analyzer that checks the `env` struct
+// tags of structs loaded with envstruct. It reports unknown, malformed or
+// conflicting tag options, environment variables read by more than one
+// field, `env` tags on unexported fields and fields of types envstruct can
+// not load.
+//
+// Types with a parser registered with envstruct.RegisterParser in the
+// analyzed package are supported. Types registered elsewhere can be listed
+// with the -types flag.
+package envstructlint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"code.cloudfoundry.org/go-envstruct/internal/gotypes"
+	"code.cloudfoundry.org/go-envstruct/internal/tags"
+)
+
+const doc = `check env struct tags used by envstruct
+
+The envstructlint analyzer reports unknown, malformed or conflicting
+options in env struct tags, environment variables read by more than one
+field, env tags on unexported fields and fields whose type envstruct can
+not load.`
+
+// Analyzer checks the `env` struct tags of structs loaded with envstruct.
+var Analyzer = &analysis.Analyzer{
+	Name:     "envstructlint",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var extraTypes string
+
+func init() {
+	Analyzer.Flags.StringVar(&extraTypes, "types", "",
+		"comma separated list of additional supported types, e.g. math/big.Int,*math/big.Int")
+}
+
+type checker struct {
+	*gotypes.Rules
+	pass *analysis.Pass
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	var extra []string
+	for _, t := range strings.Split(extraTypes, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			extra = append(extra, t)
+		}
+	}
+
+	c := &checker{
+		Rules: gotypes.NewRules(pass.TypesInfo, extra),
+		pass:  pass,
+	}
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+		st, ok := pass.TypesInfo.TypeOf(n.(*ast.StructType)).(*types.Struct)
+		if !ok {
+			return
+		}
+
+		c.checkFields(st)
+		c.checkDuplicates(st)
+	})
+
+	return nil, nil
+}
+
+func (c *checker) checkFields(st *types.Struct) {
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+
+		envTag, ok := reflect.StructTag(st.Tag(i)).Lookup(tags.Env)
+		if !ok {
+			continue
+		}
+
+		properties := tags.Split(envTag)
+		c.checkOptions(field, properties[1:])
+
+		name := properties[0]
+		if name == "" {
+			if !c.IsNested(field.Type()) {
+				c.pass.Reportf(field.Pos(), "env tag on field %s has no environment variable name", field.Name())
+			}
+
+			continue
+		}
+
+		if !field.Exported() {
+			c.pass.Reportf(field.Pos(), "env tag on unexported field %s is ignored", field.Name())
+			continue
+		}
+
+		if _, pointer := field.Type().(*types.Pointer); c.IsNested(field.Type()) && !pointer {
+			c.pass.Reportf(field.Pos(), "nested struct %s with env tag needs to have an UnmarshalEnv method", field.Name())
+			continue
+		}
+
+		if !c.Supported(field.Type()) {
+			c.pass.Reportf(field.Pos(), "unsupported type %s of field %s", field.Type(), field.Name())
+		}
+	}
+}
+
+func (c *checker) checkOptions(field *types.Var, options []string) {
+	for _, err := range tags.Check(gotypes.OptionType(field.Type()), options) {
+		msg := err.Message(field.Name())
+		if err.Problem == "" {
+			if suggestion := suggestOption(err.Options[0]); suggestion != "" {
+				msg += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+		}
+
+		c.pass.Reportf(field.Pos(), "%s", msg)
+	}
+}
+
+// checkDuplicates reports environment variables that are read by more than
+// one field of st, including the fields of nested structs. Duplicates that
+// are entirely within a nested struct are left to the check of that struct.
+func (c *checker) checkDuplicates(st *types.Struct) {
+	seen := map[string]struct {
+		index int
+		path  string
+	}{}
+
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+
+		for _, f := range c.Fields(field, st.Tag(i), field.Name()) {
+			first, ok := seen[f.EnvVar]
+			if !ok {
+				seen[f.EnvVar] = struct {
+					index int
+					path  string
+				}{i, f.Path}
+				continue
+			}
+
+			if first.index != i {
+				c.pass.Reportf(field.Pos(), "environment variable %s of %s is also read by %s", f.EnvVar, f.Path, first.path)
+			}
+		}
+	}
+}
+
+// suggestOption returns the known option closest to key, if any is close
+// enough to be a likely typo.
+func suggestOption(key string) string {
+	suggestion, best := "", 3
+	for option := range tags.Options {
+		if d := editDistance(key, option); d < best || (d == best && option < suggestion) {
+			suggestion, best = option, d
+		}
+	}
+
+	return suggestion
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,13 @@
+package envstructlint_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"code.cloudfoundry.org/go-envstruct/envstructlint"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), envstructlint.Analyzer, "a")
+}
@@ -0,0 +1,92 @@
+package a
+
+import (
+	"math/big"
+	"net/url"
+	"regexp"
+	"time"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+)
+
+func init() {
+	envstruct.RegisterParser(func(s string) (*big.Int, error) { return nil, nil })
+}
+
+type Credentials struct {
+	Username string
+	Password string
+}
+
+func (c *Credentials) UnmarshalEnv(v string) error { return nil }
+
+type Database struct {
+	Host string `env:"HOST,required"`
+	Port int    `env:"PORT"`
+}
+
+type Valid struct {
+	Name        string                   `env:"NAME,required,report"`
+	Timeout     time.Duration            `env:"TIMEOUT,extended"`
+	Cutoff      time.Time                `env:"CUTOFF,layout=2006-01-02"`
+	Posted      time.Time                `env:"POSTED,layout='Mon, 02 Jan 2006'"`
+	URL         *url.URL                 `env:"URL,redact-userinfo"`
+	Patterns    []*regexp.Regexp         `env:"PATTERNS"`
+	Key         []byte                   `env:"KEY,encoding=hex"`
+	Labels      map[string]string        `env:"LABELS"`
+	Size        envstruct.ByteSize       `env:"SIZE"`
+	Files       envstruct.Glob           `env:"FILES"`
+	Password    envstruct.Secret[string] `env:"PASSWORD,mask=last4"`
+	Token       envstruct.Secret[[]byte] `env:"TOKEN,encoding=base64url"`
+	Credentials Credentials              `env:"CREDENTIALS"`
+	Big         *big.Int                 `env:"BIG,hash"`
+	Port        *int                     `env:"OPTIONAL_PORT,optional"`
+	Any         interface{}              `env:"ANY"`
+	DB          Database                 `envprefix:"DB_"`
+	Cache       *Database                `env:",optional" envprefix:"CACHE_"`
+	Replica     *Database                `env:"REPLICA"`
+	Ignored     func()
+}
+
+type Options struct {
+	A string `env:"A,requierd"` // want `unknown env tag option "requierd" on field A, did you mean "required"\?`
+	B string `env:"B,whatever"` // want `unknown env tag option "whatever" on field B$`
+	C string `env:"C,mask"`     // want `env tag option "mask" on field C requires a value`
+	D string `env:"D,report=1"` // want `env tag option "report" on field D does not take a value`
+	E string `env:",required"`  // want `env tag on field E has no environment variable name`
+
+	F []byte        `env:"F,encoding=base32"`   // want `env tag option "encoding" on field F has invalid value "base32"`
+	G string        `env:"G,mask=first4"`       // want `env tag option "mask" on field G has invalid value "first4"`
+	H *string       `env:"H,required,optional"` // want `env tag options "required" and "optional" on field H conflict`
+	I time.Duration `env:"I,layout=2006"`       // want `env tag option "layout" on field I only applies to time.Time`
+	J string        `env:"J,mask=last4,hash"`   // want `env tag options "mask", "hash" and "redact-userinfo" on field J conflict`
+}
+
+type Fields struct {
+	unexported string            `env:"UNEXPORTED"` // want `env tag on unexported field unexported is ignored`
+	Func       func()            `env:"FUNC"`       // want `unsupported type func\(\) of field Func`
+	Chan       []chan int        `env:"CHAN"`       // want `unsupported type \[\]chan int of field Chan`
+	Nested     Database          `env:"NESTED"`     // want `nested struct Nested with env tag needs to have an UnmarshalEnv method`
+	Map        map[string]func() `env:"MAP"`        // want `unsupported type map\[string\]func\(\) of field Map`
+}
+
+type Duplicates struct {
+	Host  string   `env:"HOST"`
+	DB    Database // want `environment variable HOST of DB.Host is also read by Host`
+	Admin Database `envprefix:"ADMIN_"`
+	Port  int      `env:"ADMIN_PORT"` // want `environment variable ADMIN_PORT of Port is also read by Admin.Port`
+}
+
+type DuplicatesWithin struct {
+	A string `env:"SAME"`
+	B string `env:"SAME"` // want `environment variable SAME of B is also read by A`
+}
+
+type UsesDuplicates struct {
+	Within DuplicatesWithin
+}
+
+type Node struct {
+	Name string `env:"NAME"`
+	Next *Node  `env:",optional" envprefix:"NEXT_"`
+}
@@ -0,0 +1,14 @@
+// Package envstruct is a stub of the parts of envstruct used by the tests.
+package envstruct
+
+type ByteSize uint64
+
+func (b *ByteSize) UnmarshalEnv(v string) error { return nil }
+
+type Glob string
+
+type Secret[T any] struct{ value T }
+
+func (s *Secret[T]) UnmarshalEnv(v string) error { return nil }
+
+func RegisterParser[T any](parse func(string) (T, error)) {}
@@ -0,0 +1,99 @@
+package envstruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ParseError is returned by Load when the value of an environment variable
+// can not be parsed into the type of its field.
+type ParseError struct {
+	// EnvVar is the environment variable the value was read from.
+	EnvVar string
+
+	// Type is the type the value was parsed into.
+	Type reflect.Type
+
+	// Err is the underlying error.
+	Err error
+
+	// redacted is true if the value is a secret. The message of Err, which
+	// might quote the value, is then left out of the error message.
+	redacted bool
+}
+
+func (e *ParseError) Error() string {
+	envVar := e.EnvVar
+	if envVar == "" {
+		envVar = "value"
+	}
+
+	if e.redacted {
+		return fmt.Sprintf("failed to parse %s as %s: %s", envVar, e.Type, omittedValue)
+	}
+
+	return fmt.Sprintf("failed to parse %s as %s: %s", envVar, e.Type, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError wraps err in a ParseError for type t. Errors from strconv
+// are unwrapped so that the value, which might be a secret, does not end up
+// in the error message.
+func newParseError(t reflect.Type, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return err
+	}
+
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		err = numErr.Err
+	}
+
+	return &ParseError{Type: t, Err: err}
+}
+
+// InvalidTargetError is returned when the value passed to Load, WriteReport
+// and the other functions taking a struct is not a non-nil pointer to a
+// struct.
+type InvalidTargetError struct {
+	// Type is the type of the value that was passed, nil if the value was
+	// nil.
+	Type reflect.Type
+}
+
+func (e *InvalidTargetError) Error() string {
+	if e.Type == nil {
+		return "invalid target: nil"
+	}
+
+	if e.Type.Kind() != reflect.Pointer {
+		return fmt.Sprintf("invalid target: non-pointer %s", e.Type)
+	}
+
+	if e.Type.Elem().Kind() != reflect.Struct {
+		return fmt.Sprintf("invalid target: pointer to non-struct %s", e.Type)
+	}
+
+	return fmt.Sprintf("invalid target: nil %s", e.Type)
+}
+
+// targetStruct returns the struct t points to or an InvalidTargetError if t
+// is not a non-nil pointer to a struct.
+func targetStruct(t interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(t)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, &InvalidTargetError{Type: reflect.TypeOf(t)}
+	}
+
+	return v.Elem(), nil
+}
@@ -0,0 +1,73 @@
+package envstruct_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type NilUnmarshallerTestStruct struct {
+	Unmarshaller *spyUnmarshaller `env:"NIL_UNMARSHALLER"`
+}
+
+var _ = Describe("invalid targets and nil values", func() {
+	var (
+		nilStruct *ToEnvTestStruct
+		str       string
+	)
+
+	DescribeTable("returns an error for invalid targets", func(t interface{}, message string) {
+		var buf bytes.Buffer
+		envstruct.ReportWriter = &buf
+		defer func() { envstruct.ReportWriter = os.Stderr }()
+
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+		for _, err := range []error{
+			envstruct.Load(t),
+			envstruct.WriteReport(t),
+			envstruct.WriteReport(t, envstruct.WithFormat(envstruct.FormatJSON)),
+			envstruct.WriteUsage(&buf, t),
+			envstruct.LogReport(logger, t),
+			func() error { _, err := envstruct.Report(t); return err }(),
+			func() error { _, err := envstruct.FormatEnv(t); return err }(),
+		} {
+			var targetErr *envstruct.InvalidTargetError
+			Expect(errors.As(err, &targetErr)).To(BeTrue())
+			Expect(err).To(MatchError(message))
+		}
+
+		Expect(envstruct.ToEnv(t)).To(BeNil())
+		Expect(envstruct.LogValue(t).String()).To(Equal("!ERROR: " + message))
+		Expect(buf.String()).To(BeEmpty())
+	},
+		Entry("nil", nil, "invalid target: nil"),
+		Entry("a struct", ToEnvTestStruct{}, "invalid target: non-pointer envstruct_test.ToEnvTestStruct"),
+		Entry("a pointer to a non-struct", &str, "invalid target: pointer to non-struct *string"),
+		Entry("a nil pointer", nilStruct, "invalid target: nil *envstruct_test.ToEnvTestStruct"),
+	)
+
+	It("allocates nil pointers to unmarshallers", func() {
+		os.Setenv("NIL_UNMARSHALLER", "value")
+		defer os.Unsetenv("NIL_UNMARSHALLER")
+
+		ts := NilUnmarshallerTestStruct{}
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+		Expect(ts.Unmarshaller.UnmarshalEnvInput).To(Equal("value"))
+	})
+
+	It("leaves nil pointers to unmarshallers nil if the variable is not set", func() {
+		ts := NilUnmarshallerTestStruct{}
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+		Expect(ts.Unmarshaller).To(BeNil())
+	})
+})
@@ -17,9 +17,10 @@ func (c *Credentials) UnmarshalEnv(data string) error {
 }
 
 type HostInfo struct {
-	Credentials Credentials `env:"CREDENTIALS, required"`
-	IP          string      `env:"HOST_IP,     required, report"`
-	Port        int         `env:"HOST_PORT,             report"`
+	Credentials Credentials              `env:"CREDENTIALS, required"`
+	IP          string                   `env:"HOST_IP,     required, report"`
+	Port        int                      `env:"HOST_PORT,             report"`
+	Password    envstruct.Secret[string] `env:"PASSWORD"`
 }
 
 func main() {
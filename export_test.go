@@ -0,0 +1,48 @@
+package envstruct
+
+import "reflect"
+
+// Fingerprint exposes the fingerprint of the struct tags of a type to the
+// tests.
+var Fingerprint = fingerprint
+
+// ResetPlans discards the cached plans of struct types so that benchmarks
+// can compare against uncached loading.
+var ResetPlans = resetPlans
+
+// ResetRegistry removes the parsers and formatters registered by the tests.
+var ResetRegistry = resetRegistry
+
+// BuiltinTypeNames returns the types with a built-in setter or formatter as
+// formatted by types.TypeString without a qualifier.
+func BuiltinTypeNames() []string {
+	seen := map[string]bool{}
+	for t := range typeSetters {
+		seen[typeString(t)] = true
+	}
+	for t := range typeFormatters {
+		seen[typeString(t)] = true
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func typeString(t reflect.Type) string {
+	if t.Name() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + typeString(t.Elem())
+	case reflect.Slice:
+		return "[]" + typeString(t.Elem())
+	}
+
+	return t.String()
+}
@@ -0,0 +1,286 @@
+package envstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"code.cloudfoundry.org/go-envstruct/internal/tags"
+)
+
+// The methods generated by envstructgen. Their names are prefixed so that
+// methods written by hand, such as a ToEnv method calling envstruct.ToEnv,
+// are never mistaken for them.
+type (
+	generated interface {
+		EnvstructFingerprint() string
+	}
+
+	envLoader interface {
+		generated
+		EnvstructLoadEnv() error
+	}
+
+	envFormatter interface {
+		generated
+		EnvstructToEnv() []string
+	}
+
+	envReporter interface {
+		generated
+		EnvstructReport() []ReportField
+	}
+)
+
+// fingerprints caches the fingerprint of the struct tags of a type.
+var fingerprints sync.Map
+
+// checkGenerated returns an error if the methods of t were generated by
+// envstructgen for struct tags that differ from those of the type of val.
+func checkGenerated(t generated, val reflect.Value) error {
+	if t.EnvstructFingerprint() != fingerprint(val.Type()) {
+		return fmt.Errorf("methods of %s generated by envstructgen are out of date, run go generate", val.Type())
+	}
+
+	return nil
+}
+
+// fingerprint returns the fingerprint of the struct tags of t as computed
+// by envstructgen: the path and tag of every field with an `env` or
+// `envprefix` tag, including the fields of nested structs.
+func fingerprint(t reflect.Type) string {
+	if fp, ok := fingerprints.Load(t); ok {
+		return fp.(string)
+	}
+
+	fp := tags.Fingerprint(taggedFields(t, "", nil))
+	fingerprints.Store(t, fp)
+
+	return fp
+}
+
+func taggedFields(t reflect.Type, path string, fields []string) []string {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldPath := path + "." + field.Name
+
+		if tags.Tagged(field.Tag) {
+			fields = append(fields, fieldPath+" "+string(field.Tag))
+		}
+
+		if field.Type.Kind() == reflect.Struct && tags.Split(field.Tag.Get(tags.Env))[0] == "" {
+			fields = taggedFields(field.Type, fieldPath, fields)
+		}
+	}
+
+	return fields
+}
+
+// LoadField loads the field v points to as Load loads a field with the
+// struct tag tag, with prefix added to the environment variables. It returns
+// the required environment variables that are missing. It is used by code
+// generated by envstructgen.
+func LoadField(v interface{}, tag, prefix string) (missing []string, err error) {
+	value, err := fieldValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadField(value, planForField(value.Type(), tag, prefix))
+}
+
+// FormatField returns the environment of the field v points to as ToEnv
+// does for a field with the struct tag tag, with prefix added to the
+// environment variables. As ToEnv, it returns nil if v is not a non-nil
+// pointer. It is used by code generated by envstructgen.
+func FormatField(v interface{}, tag, prefix string) []string {
+	value, err := fieldValue(v)
+	if err != nil {
+		return nil
+	}
+
+	return fieldEnv(value, planForField(value.Type(), tag, prefix), prefix)
+}
+
+// ReportField names a field visited by Report and the other functions that
+// display the values of a struct. It is used by code generated by
+// envstructgen to list the fields of a struct, including those of nested
+// structs, without walking it.
+type ReportField struct {
+	// Struct points to the struct declaring the field.
+	Struct interface{}
+
+	// Name is the name of the field.
+	Name string
+
+	// Path is the path to the field from the root struct, e.g.
+	// `Config.Database.Host`.
+	Path string
+
+	// Prefix is added to the environment variables of the fields of Struct.
+	Prefix string
+}
+
+// walkReportFields calls fn for the fields listed by the EnvstructReport
+// method of the struct type t, as walkEnvFieldsWithPath does for the fields
+// of t.
+func walkReportFields(t reflect.Type, fields []ReportField, fn func(envField) error) error {
+	visiting := map[reflect.Type]bool{t: true}
+
+	for _, rf := range fields {
+		val, err := fieldValue(rf.Struct)
+		if err != nil {
+			return err
+		}
+
+		field, ok := val.Type().FieldByName(rf.Name)
+		if !ok || len(field.Index) != 1 {
+			return fmt.Errorf("methods of %s generated by envstructgen are out of date, run go generate", t)
+		}
+
+		i := field.Index[0]
+		plan := planFor(val.Type(), rf.Prefix)
+
+		err = walkField(val.Field(i), &plan.fields[i], val.Type().Name(), rf.Path, visiting, fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Basic is the set of types that code generated by envstructgen parses and
+// formats with ParseValue and FormatValue.
+type Basic interface {
+	bool | string |
+		int | int8 | int16 | int32 | int64 |
+		uint | uint8 | uint16 | uint32 | uint64 |
+		float32 | float64
+}
+
+// ParseValue parses the value v of the environment variable envVar into a T
+// as Load parses it for a field of type T, including parsers registered with
+// RegisterParser. It is used by code generated by envstructgen.
+func ParseValue[T Basic](envVar, v string) (T, error) {
+	var t T
+
+	if set, ok := registeredSetter(typeOf[T]()); ok {
+		err := set(reflect.ValueOf(&t).Elem(), v, nil)
+		return t, withEnvVar(newParseError(typeOf[T](), err), envVar)
+	}
+
+	var err error
+	switch p := any(&t).(type) {
+	case *bool:
+		*p = parseBool(v)
+	case *string:
+		*p = v
+	case *int:
+		*p, err = parseSigned[int](v, strconv.IntSize)
+	case *int8:
+		*p, err = parseSigned[int8](v, 8)
+	case *int16:
+		*p, err = parseSigned[int16](v, 16)
+	case *int32:
+		*p, err = parseSigned[int32](v, 32)
+	case *int64:
+		*p, err = parseSigned[int64](v, 64)
+	case *uint:
+		*p, err = parseUnsigned[uint](v, strconv.IntSize)
+	case *uint8:
+		*p, err = parseUnsigned[uint8](v, 8)
+	case *uint16:
+		*p, err = parseUnsigned[uint16](v, 16)
+	case *uint32:
+		*p, err = parseUnsigned[uint32](v, 32)
+	case *uint64:
+		*p, err = parseUnsigned[uint64](v, 64)
+	case *float32:
+		var n float64
+		n, err = strconv.ParseFloat(v, 64)
+		*p = float32(n)
+	case *float64:
+		*p, err = strconv.ParseFloat(v, 64)
+	}
+
+	return t, withEnvVar(newParseError(typeOf[T](), err), envVar)
+}
+
+func parseSigned[T int | int8 | int16 | int32 | int64](v string, bits int) (T, error) {
+	n, err := parseInt(v, bits)
+	return T(n), err
+}
+
+func parseUnsigned[T uint | uint8 | uint16 | uint32 | uint64](v string, bits int) (T, error) {
+	n, err := parseUint(v, bits)
+	return T(n), err
+}
+
+// FormatValue formats v as ToEnv formats a field of type T, including
+// formatters registered with RegisterFormatter. It is used by code generated
+// by envstructgen.
+func FormatValue[T Basic](v T) string {
+	if format, ok := registeredFormatter(typeOf[T]()); ok {
+		return format(reflect.ValueOf(&v).Elem(), nil)
+	}
+
+	switch v := any(v).(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	case int:
+		return strconv.FormatInt(int64(v), 10)
+	case int8:
+		return strconv.FormatInt(int64(v), 10)
+	case int16:
+		return strconv.FormatInt(int64(v), 10)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+
+	return ""
+}
+
+// MissingError returns the error Load returns for missing required
+// environment variables, or nil if none are missing. It is used by code
+// generated by envstructgen.
+func MissingError(missing []string) error {
+	return missingError(missing)
+}
+
+// withEnvVar sets the environment variable of err if it is a ParseError.
+func withEnvVar(err error, envVar string) error {
+	if parseErr, ok := err.(*ParseError); ok {
+		parseErr.EnvVar = envVar
+	}
+
+	return err
+}
+
+func fieldValue(v interface{}) (reflect.Value, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return reflect.Value{}, &InvalidTargetError{Type: reflect.TypeOf(v)}
+	}
+
+	return value.Elem(), nil
+}
@@ -0,0 +1,278 @@
+package envstruct_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strconv"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type GeneratedTestStruct struct {
+	Port int `env:"GENERATED_PORT,required,report"`
+
+	loaded bool
+}
+
+func (s *GeneratedTestStruct) EnvstructFingerprint() string {
+	return envstruct.Fingerprint(reflect.TypeOf(*s))
+}
+
+func (s *GeneratedTestStruct) EnvstructLoadEnv() error {
+	s.loaded = true
+	return nil
+}
+
+func (s *GeneratedTestStruct) EnvstructToEnv() []string {
+	return []string{"GENERATED_PORT=generated"}
+}
+
+func (s *GeneratedTestStruct) EnvstructReport() []envstruct.ReportField {
+	return []envstruct.ReportField{
+		{Struct: s, Name: "Port", Path: "Generated.Port", Prefix: "GENERATED_PREFIX_"},
+	}
+}
+
+type StaleGeneratedTestStruct struct {
+	Port int `env:"GENERATED_PORT,required,report"`
+}
+
+func (s *StaleGeneratedTestStruct) EnvstructFingerprint() string {
+	return "0000000000000000"
+}
+
+func (s *StaleGeneratedTestStruct) EnvstructLoadEnv() error {
+	return errors.New("stale method called")
+}
+
+func (s *StaleGeneratedTestStruct) EnvstructToEnv() []string {
+	return []string{"GENERATED_PORT=stale"}
+}
+
+func (s *StaleGeneratedTestStruct) EnvstructReport() []envstruct.ReportField {
+	return []envstruct.ReportField{{Struct: s, Name: "Removed", Path: "Stale.Removed"}}
+}
+
+// HandWrittenTestStruct has methods named like the ones envstructgen used to
+// generate, which call envstruct.
+type HandWrittenTestStruct struct {
+	Port int `env:"GENERATED_PORT,report"`
+}
+
+func (s *HandWrittenTestStruct) LoadEnv() error {
+	return envstruct.Load(s)
+}
+
+func (s *HandWrittenTestStruct) ToEnv() []string {
+	return envstruct.ToEnv(s)
+}
+
+func (s *HandWrittenTestStruct) Report() ([]envstruct.ReportEntry, error) {
+	return envstruct.Report(s)
+}
+
+var _ = Describe("Generated", func() {
+	AfterEach(func() {
+		Expect(os.Unsetenv("GENERATED_PORT")).To(Succeed())
+		Expect(os.Unsetenv("GENERATED_PREFIX_PORT")).To(Succeed())
+	})
+
+	Context("when a struct has generated methods", func() {
+		It("Load calls EnvstructLoadEnv", func() {
+			s := GeneratedTestStruct{}
+
+			Expect(envstruct.Load(&s)).To(Succeed())
+			Expect(s.loaded).To(BeTrue())
+		})
+
+		It("ToEnv calls EnvstructToEnv", func() {
+			Expect(envstruct.ToEnv(&GeneratedTestStruct{Port: 80})).To(Equal([]string{"GENERATED_PORT=generated"}))
+		})
+
+		It("Report reports the fields listed by EnvstructReport", func() {
+			entries, err := envstruct.Report(&GeneratedTestStruct{Port: 8080})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(Equal([]envstruct.ReportEntry{{
+				Field:    "Generated.Port",
+				Type:     "int",
+				EnvVar:   "GENERATED_PREFIX_GENERATED_PORT",
+				Required: true,
+				Value:    "8080",
+				Source:   envstruct.SourceDefault,
+			}}))
+		})
+
+		It("still rejects invalid targets", func() {
+			var s *GeneratedTestStruct
+
+			Expect(envstruct.Load(s)).To(MatchError(&envstruct.InvalidTargetError{Type: reflect.TypeOf(s)}))
+		})
+	})
+
+	Context("when the generated methods are out of date", func() {
+		It("Load returns an error", func() {
+			Expect(envstruct.Load(&StaleGeneratedTestStruct{})).To(MatchError(
+				"methods of envstruct_test.StaleGeneratedTestStruct generated by envstructgen are out of date, run go generate",
+			))
+		})
+
+		It("ToEnv uses reflection", func() {
+			Expect(envstruct.ToEnv(&StaleGeneratedTestStruct{Port: 80})).To(Equal([]string{"GENERATED_PORT=80"}))
+		})
+
+		It("Report uses reflection", func() {
+			entries, err := envstruct.Report(&StaleGeneratedTestStruct{Port: 80})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(ConsistOf(HaveField("Field", "StaleGeneratedTestStruct.Port")))
+		})
+	})
+
+	Context("when a struct has methods that are not generated", func() {
+		It("does not call them", func() {
+			Expect(os.Setenv("GENERATED_PORT", "8080")).To(Succeed())
+			s := HandWrittenTestStruct{}
+
+			Expect(s.LoadEnv()).To(Succeed())
+			Expect(s.Port).To(Equal(8080))
+			Expect(s.ToEnv()).To(Equal([]string{"GENERATED_PORT=8080"}))
+
+			entries, err := s.Report()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+		})
+	})
+
+	Describe("Fingerprint", func() {
+		It("changes with the struct tags of nested structs", func() {
+			type inner struct {
+				Host string `env:"HOST"`
+			}
+			type renamed struct {
+				Host string `env:"HOSTNAME"`
+			}
+
+			a := envstruct.Fingerprint(reflect.TypeOf(struct{ DB inner }{}))
+			b := envstruct.Fingerprint(reflect.TypeOf(struct{ DB renamed }{}))
+
+			Expect(a).ToNot(Equal(b))
+		})
+
+		It("ignores fields without struct tags", func() {
+			a := envstruct.Fingerprint(reflect.TypeOf(struct {
+				Port int `env:"PORT"`
+			}{}))
+			b := envstruct.Fingerprint(reflect.TypeOf(struct {
+				Port  int `env:"PORT"`
+				Other int
+			}{}))
+
+			Expect(a).To(Equal(b))
+		})
+	})
+
+	Describe("LoadField", func() {
+		It("loads a field with the prefix", func() {
+			Expect(os.Setenv("GENERATED_PREFIX_PORT", "8080")).To(Succeed())
+
+			var port int
+			missing, err := envstruct.LoadField(&port, `env:"PORT,required"`, "GENERATED_PREFIX_")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(missing).To(BeEmpty())
+			Expect(port).To(Equal(8080))
+		})
+
+		It("returns missing required environment variables", func() {
+			var port int
+			missing, err := envstruct.LoadField(&port, `env:"GENERATED_PORT,required"`, "")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(missing).To(Equal([]string{"GENERATED_PORT"}))
+		})
+
+		It("returns parse errors", func() {
+			Expect(os.Setenv("GENERATED_PORT", "port")).To(Succeed())
+
+			var port int
+			_, err := envstruct.LoadField(&port, `env:"GENERATED_PORT"`, "")
+
+			var parseErr *envstruct.ParseError
+			Expect(errors.As(err, &parseErr)).To(BeTrue())
+			Expect(parseErr.EnvVar).To(Equal("GENERATED_PORT"))
+		})
+	})
+
+	Describe("FormatField", func() {
+		It("formats a field with the prefix", func() {
+			port := 8080
+
+			Expect(envstruct.FormatField(&port, `env:"PORT"`, "GENERATED_PREFIX_")).To(Equal([]string{"GENERATED_PREFIX_PORT=8080"}))
+		})
+
+		It("returns nil as ToEnv does if v is not a non-nil pointer", func() {
+			var port *int
+
+			Expect(envstruct.FormatField(8080, `env:"PORT"`, "")).To(BeNil())
+			Expect(envstruct.FormatField(port, `env:"PORT"`, "")).To(BeNil())
+		})
+	})
+
+	Describe("ParseValue", func() {
+		It("parses values as Load does", func() {
+			Expect(envstruct.ParseValue[int16]("GENERATED_PORT", "0x10")).To(Equal(int16(16)))
+			Expect(envstruct.ParseValue[int]("GENERATED_PORT", "08080")).To(Equal(8080))
+			Expect(envstruct.ParseValue[uint]("GENERATED_PORT", "0100")).To(Equal(uint(100)))
+			Expect(envstruct.ParseValue[bool]("GENERATED_PORT", "1")).To(BeTrue())
+			Expect(envstruct.ParseValue[float32]("GENERATED_PORT", "0.5")).To(Equal(float32(0.5)))
+			Expect(envstruct.ParseValue[string]("GENERATED_PORT", "port")).To(Equal("port"))
+		})
+
+		It("returns the ParseError Load returns", func() {
+			Expect(os.Setenv("GENERATED_PORT", "70000")).To(Succeed())
+			var s struct {
+				Port uint16 `env:"GENERATED_PORT"`
+			}
+
+			_, err := envstruct.ParseValue[uint16]("GENERATED_PORT", "70000")
+
+			Expect(err).To(MatchError(envstruct.Load(&s).Error()))
+		})
+	})
+
+	Describe("FormatValue", func() {
+		It("formats values as ToEnv does", func() {
+			s := struct {
+				Int   int8    `env:"INT"`
+				Uint  uint    `env:"UINT"`
+				Float float32 `env:"FLOAT"`
+				Bool  bool    `env:"BOOL"`
+			}{-8, 8, 0.1, true}
+
+			Expect(envstruct.ToEnv(&s)).To(Equal([]string{
+				"INT=" + envstruct.FormatValue(s.Int),
+				"UINT=" + envstruct.FormatValue(s.Uint),
+				"FLOAT=" + envstruct.FormatValue(s.Float),
+				"BOOL=" + envstruct.FormatValue(s.Bool),
+			}))
+			Expect(envstruct.FormatValue(s.Float)).To(Equal(strconv.FormatFloat(0.1, 'g', -1, 32)))
+		})
+	})
+
+	Describe("MissingError", func() {
+		It("returns nil without missing environment variables", func() {
+			Expect(envstruct.MissingError(nil)).To(Succeed())
+		})
+
+		It("returns the error Load returns", func() {
+			var s struct {
+				Port int `env:"GENERATED_PORT,required"`
+			}
+
+			Expect(envstruct.MissingError([]string{"GENERATED_PORT"})).To(MatchError(envstruct.Load(&s).Error()))
+		})
+	})
+})
@@ -0,0 +1,78 @@
+package envstruct
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// GetOption configures Get.
+type GetOption func(*getOptions)
+
+type getOptions struct {
+	tagProperties []string
+	defaultValue  string
+}
+
+// Required makes Get return an error if the environment variable is empty
+// and there is no default.
+func Required() GetOption {
+	return func(o *getOptions) {
+		o.tagProperties = append(o.tagProperties, tagRequired)
+	}
+}
+
+// WithDefault sets the value that is parsed when the environment variable is
+// empty.
+func WithDefault(v string) GetOption {
+	return func(o *getOptions) {
+		o.defaultValue = v
+	}
+}
+
+// WithTagOptions sets options as they would be given in the `env` struct
+// tag, e.g. `layout=2006-01-02` or `encoding=hex`.
+func WithTagOptions(options ...string) GetOption {
+	return func(o *getOptions) {
+		o.tagProperties = append(o.tagProperties, options...)
+	}
+}
+
+// Get returns the value of the environment variable name parsed into a T, in
+// the same way Load would populate a struct field of type T.
+func Get[T any](name string, opts ...GetOption) (T, error) {
+	o := getOptions{
+		tagProperties: []string{name},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	envVal := os.Getenv(name)
+	if envVal == "" {
+		envVal = o.defaultValue
+	}
+
+	var v T
+	f := valuePlan(typeOf[T](), o.tagProperties)
+	missing, err := loadValue(reflect.ValueOf(&v).Elem(), envVal, &f)
+	if err != nil {
+		return v, err
+	}
+
+	if err := missingError(missing); err != nil {
+		return v, err
+	}
+
+	return v, nil
+}
+
+// MustGet is like Get but panics if the value can not be loaded.
+func MustGet[T any](name string, opts ...GetOption) T {
+	v, err := Get[T](name, opts...)
+	if err != nil {
+		panic(fmt.Sprintf("envstruct: %s", err))
+	}
+
+	return v
+}
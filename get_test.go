@@ -0,0 +1,101 @@
+package envstruct_test
+
+import (
+	"errors"
+	"net/netip"
+	"os"
+	"time"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Get()", func() {
+	AfterEach(func() {
+		os.Unsetenv("GET_THING")
+	})
+
+	It("parses the environment variable", func() {
+		os.Setenv("GET_THING", "8080")
+
+		port, err := envstruct.Get[int]("GET_THING")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(port).To(Equal(8080))
+	})
+
+	It("supports the same types as Load", func() {
+		os.Setenv("GET_THING", "10.0.0.0/8, 192.168.0.0/16")
+
+		prefixes, err := envstruct.Get[[]netip.Prefix]("GET_THING")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(prefixes).To(Equal([]netip.Prefix{
+			netip.MustParsePrefix("10.0.0.0/8"),
+			netip.MustParsePrefix("192.168.0.0/16"),
+		}))
+	})
+
+	It("returns the zero value if the environment variable is empty", func() {
+		port, err := envstruct.Get[int]("GET_THING")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(port).To(BeZero())
+	})
+
+	It("uses the default if the environment variable is empty", func() {
+		timeout, err := envstruct.Get[time.Duration]("GET_THING", envstruct.WithDefault("30s"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(timeout).To(Equal(30 * time.Second))
+	})
+
+	It("supports tag options", func() {
+		os.Setenv("GET_THING", "2026-10-19")
+
+		date, err := envstruct.Get[time.Time]("GET_THING", envstruct.WithTagOptions("layout=2006-01-02"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(date).To(Equal(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("supports secrets", func() {
+		os.Setenv("GET_THING", "hunter2")
+
+		password, err := envstruct.Get[envstruct.Secret[string]]("GET_THING")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(password.Value()).To(Equal("hunter2"))
+	})
+
+	It("returns an error if a required environment variable is empty", func() {
+		_, err := envstruct.Get[string]("GET_THING", envstruct.Required())
+		Expect(err).To(MatchError("missing required environment variables: GET_THING"))
+	})
+
+	It("accepts a default for a required environment variable", func() {
+		host, err := envstruct.Get[string]("GET_THING", envstruct.Required(), envstruct.WithDefault("localhost"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(host).To(Equal("localhost"))
+	})
+
+	It("returns a parse error for an invalid value", func() {
+		os.Setenv("GET_THING", "300")
+
+		_, err := envstruct.Get[int8]("GET_THING")
+
+		var parseErr *envstruct.ParseError
+		Expect(errors.As(err, &parseErr)).To(BeTrue())
+		Expect(err).To(MatchError("failed to parse GET_THING as int8: value out of range"))
+	})
+
+	Describe("MustGet()", func() {
+		It("returns the value", func() {
+			os.Setenv("GET_THING", "true")
+
+			Expect(envstruct.MustGet[bool]("GET_THING")).To(BeTrue())
+		})
+
+		It("panics if the value can not be loaded", func() {
+			Expect(func() {
+				envstruct.MustGet[string]("GET_THING", envstruct.Required())
+			}).To(PanicWith("envstruct: missing required environment variables: GET_THING"))
+		})
+	})
+})
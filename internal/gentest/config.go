@@ -0,0 +1,38 @@
+// Package gentest holds a struct with methods generated by envstructgen. Its
+// tests check that envstruct behaves the same with and without the
+// generated methods.
+package gentest
+
+import (
+	"time"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+)
+
+//go:generate go run code.cloudfoundry.org/go-envstruct/cmd/envstructgen -type Config
+
+type Database struct {
+	Host string `env:"HOST,required,report"`
+	Port uint16 `env:"PORT,report"`
+}
+
+type embedded struct {
+	Region string `env:"REGION,report"`
+}
+
+type Config struct {
+	embedded
+
+	Name    string                   `env:"NAME,required,report"`
+	Debug   bool                     `env:"DEBUG,report"`
+	Count   int8                     `env:"COUNT,report"`
+	Size    int                      `env:"SIZE,report"`
+	Ratio   float32                  `env:"RATIO,report"`
+	Level   string                   `env:"LEVEL,enum=debug|info,report"`
+	Timeout time.Duration            `env:"TIMEOUT,report"`
+	Hosts   []string                 `env:"HOSTS,report"`
+	Token   envstruct.Secret[string] `env:"TOKEN"`
+	DB      Database                 `envprefix:"PRIMARY_"`
+	Replica *Database                `env:",optional" envprefix:"REPLICA_"`
+	Ignored int
+}
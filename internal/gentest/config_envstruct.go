@@ -0,0 +1,160 @@
+// Code generated by envstructgen; DO NOT EDIT.
+
+package gentest
+
+import (
+	"os"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+)
+
+// EnvstructFingerprint returns the fingerprint of the struct tags the
+// methods of c were generated for.
+func (c *Config) EnvstructFingerprint() string {
+	return "1f0e41bc5dd58cfb"
+}
+
+// EnvstructLoadEnv loads c from the environment. It is used by envstruct.Load.
+func (c *Config) EnvstructLoadEnv() error {
+	var missing []string
+
+	if v := os.Getenv("REGION"); v != "" {
+		parsed, err := envstruct.ParseValue[string]("REGION", v)
+		if err != nil {
+			return err
+		}
+		c.embedded.Region = parsed
+	}
+
+	if v := os.Getenv("NAME"); v != "" {
+		parsed, err := envstruct.ParseValue[string]("NAME", v)
+		if err != nil {
+			return err
+		}
+		c.Name = parsed
+	} else {
+		missing = append(missing, "NAME")
+	}
+
+	if v := os.Getenv("DEBUG"); v != "" {
+		parsed, err := envstruct.ParseValue[bool]("DEBUG", v)
+		if err != nil {
+			return err
+		}
+		c.Debug = parsed
+	}
+
+	if v := os.Getenv("COUNT"); v != "" {
+		parsed, err := envstruct.ParseValue[int8]("COUNT", v)
+		if err != nil {
+			return err
+		}
+		c.Count = parsed
+	}
+
+	if v := os.Getenv("SIZE"); v != "" {
+		parsed, err := envstruct.ParseValue[int]("SIZE", v)
+		if err != nil {
+			return err
+		}
+		c.Size = parsed
+	}
+
+	if v := os.Getenv("RATIO"); v != "" {
+		parsed, err := envstruct.ParseValue[float32]("RATIO", v)
+		if err != nil {
+			return err
+		}
+		c.Ratio = parsed
+	}
+
+	if m, err := envstruct.LoadField(&c.Level, `env:"LEVEL,enum=debug|info,report"`, ""); err != nil {
+		return err
+	} else {
+		missing = append(missing, m...)
+	}
+
+	if m, err := envstruct.LoadField(&c.Timeout, `env:"TIMEOUT,report"`, ""); err != nil {
+		return err
+	} else {
+		missing = append(missing, m...)
+	}
+
+	if m, err := envstruct.LoadField(&c.Hosts, `env:"HOSTS,report"`, ""); err != nil {
+		return err
+	} else {
+		missing = append(missing, m...)
+	}
+
+	if m, err := envstruct.LoadField(&c.Token, `env:"TOKEN"`, ""); err != nil {
+		return err
+	} else {
+		missing = append(missing, m...)
+	}
+
+	if v := os.Getenv("PRIMARY_HOST"); v != "" {
+		parsed, err := envstruct.ParseValue[string]("PRIMARY_HOST", v)
+		if err != nil {
+			return err
+		}
+		c.DB.Host = parsed
+	} else {
+		missing = append(missing, "PRIMARY_HOST")
+	}
+
+	if v := os.Getenv("PRIMARY_PORT"); v != "" {
+		parsed, err := envstruct.ParseValue[uint16]("PRIMARY_PORT", v)
+		if err != nil {
+			return err
+		}
+		c.DB.Port = parsed
+	}
+
+	if m, err := envstruct.LoadField(&c.Replica, `env:",optional" envprefix:"REPLICA_"`, ""); err != nil {
+		return err
+	} else {
+		missing = append(missing, m...)
+	}
+
+	return envstruct.MissingError(missing)
+}
+
+// EnvstructToEnv returns the environment of c. It is used by envstruct.ToEnv.
+func (c *Config) EnvstructToEnv() []string {
+	var env []string
+	env = append(env, "REGION="+envstruct.FormatValue(c.embedded.Region))
+	env = append(env, "NAME="+envstruct.FormatValue(c.Name))
+	env = append(env, "DEBUG="+envstruct.FormatValue(c.Debug))
+	env = append(env, "COUNT="+envstruct.FormatValue(c.Count))
+	env = append(env, "SIZE="+envstruct.FormatValue(c.Size))
+	env = append(env, "RATIO="+envstruct.FormatValue(c.Ratio))
+	env = append(env, envstruct.FormatField(&c.Level, `env:"LEVEL,enum=debug|info,report"`, "")...)
+	env = append(env, envstruct.FormatField(&c.Timeout, `env:"TIMEOUT,report"`, "")...)
+	env = append(env, envstruct.FormatField(&c.Hosts, `env:"HOSTS,report"`, "")...)
+	env = append(env, envstruct.FormatField(&c.Token, `env:"TOKEN"`, "")...)
+	env = append(env, "PRIMARY_HOST="+envstruct.FormatValue(c.DB.Host))
+	env = append(env, "PRIMARY_PORT="+envstruct.FormatValue(c.DB.Port))
+	env = append(env, envstruct.FormatField(&c.Replica, `env:",optional" envprefix:"REPLICA_"`, "")...)
+
+	return env
+}
+
+// EnvstructReport returns the fields of c that are reported. It is used by
+// envstruct.Report and envstruct.WriteReport.
+func (c *Config) EnvstructReport() []envstruct.ReportField {
+	return []envstruct.ReportField{
+		{Struct: c, Name: "embedded", Path: "Config.embedded"},
+		{Struct: c, Name: "Name", Path: "Config.Name"},
+		{Struct: c, Name: "Debug", Path: "Config.Debug"},
+		{Struct: c, Name: "Count", Path: "Config.Count"},
+		{Struct: c, Name: "Size", Path: "Config.Size"},
+		{Struct: c, Name: "Ratio", Path: "Config.Ratio"},
+		{Struct: c, Name: "Level", Path: "Config.Level"},
+		{Struct: c, Name: "Timeout", Path: "Config.Timeout"},
+		{Struct: c, Name: "Hosts", Path: "Config.Hosts"},
+		{Struct: c, Name: "Token", Path: "Config.Token"},
+		{Struct: &c.DB, Name: "Host", Path: "Config.DB.Host", Prefix: "PRIMARY_"},
+		{Struct: &c.DB, Name: "Port", Path: "Config.DB.Port", Prefix: "PRIMARY_"},
+		{Struct: c, Name: "Replica", Path: "Config.Replica"},
+	}
+}
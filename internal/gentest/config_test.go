@@ -0,0 +1,175 @@
+package gentest
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+)
+
+// reflected has the fields of Config but not its generated methods, so that
+// envstruct loads it with reflection.
+type reflected Config
+
+func setenv(t *testing.T, env map[string]string) {
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+}
+
+var validEnv = map[string]string{
+	"REGION":       "eu",
+	"NAME":         "app",
+	"DEBUG":        "1",
+	"COUNT":        "-12",
+	"SIZE":         "0x10",
+	"RATIO":        "0.5",
+	"LEVEL":        "info",
+	"TIMEOUT":      "3s",
+	"HOSTS":        "a,b",
+	"TOKEN":        "secret",
+	"PRIMARY_HOST": "db",
+	"PRIMARY_PORT": "5432",
+	"REPLICA_HOST": "replica",
+}
+
+func TestGeneratedMethodsMatchReflection(t *testing.T) {
+	setenv(t, validEnv)
+
+	var generated Config
+	if err := envstruct.Load(&generated); err != nil {
+		t.Fatalf("Load with generated methods: %s", err)
+	}
+
+	var expected reflected
+	if err := envstruct.Load(&expected); err != nil {
+		t.Fatalf("Load with reflection: %s", err)
+	}
+
+	if !reflect.DeepEqual(reflected(generated), expected) {
+		t.Errorf("loaded %+v, want %+v", generated, expected)
+	}
+
+	if got, want := envstruct.ToEnv(&generated), envstruct.ToEnv(&expected); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToEnv returned %q, want %q", got, want)
+	}
+}
+
+func TestGeneratedReportMatchesReflection(t *testing.T) {
+	setenv(t, validEnv)
+
+	var generated Config
+	if err := envstruct.Load(&generated); err != nil {
+		t.Fatalf("Load with generated methods: %s", err)
+	}
+	expected := reflected(generated)
+
+	got, err := envstruct.Report(&generated, envstruct.WithRedactPatterns("*HOST*"))
+	if err != nil {
+		t.Fatalf("Report with generated methods: %s", err)
+	}
+
+	want, err := envstruct.Report(&expected, envstruct.WithRedactPatterns("*HOST*"))
+	if err != nil {
+		t.Fatalf("Report with reflection: %s", err)
+	}
+
+	// The reports only differ in the name of the root struct.
+	for i := range want {
+		want[i].Field = "Config" + strings.TrimPrefix(want[i].Field, "reflected")
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Report returned %+v, want %+v", got, want)
+	}
+
+	if got, want := writeReport(t, &generated), writeReport(t, &expected); !reflect.DeepEqual(got, want) {
+		t.Errorf("WriteReport wrote %q, want %q", got, want)
+	}
+}
+
+// writeReport returns the columns of the report WriteReport writes for v,
+// with the name of the root struct replaced by Config.
+func writeReport(t *testing.T, v interface{}) [][]string {
+	var b bytes.Buffer
+	w := envstruct.ReportWriter
+	envstruct.ReportWriter = &b
+	defer func() { envstruct.ReportWriter = w }()
+
+	if err := envstruct.WriteReport(v); err != nil {
+		t.Fatalf("WriteReport: %s", err)
+	}
+
+	var rows [][]string
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		columns := strings.Fields(line)
+		if strings.HasPrefix(columns[0], "reflected.") {
+			columns[0] = "Config" + strings.TrimPrefix(columns[0], "reflected")
+		}
+		rows = append(rows, columns)
+	}
+
+	return rows
+}
+
+func TestGeneratedMethodsUseRegisteredParsers(t *testing.T) {
+	setenv(t, validEnv)
+	t.Setenv("RATIO", "half")
+
+	envstruct.RegisterParser(func(v string) (float32, error) {
+		if v == "half" {
+			return 0.5, nil
+		}
+
+		n, err := strconv.ParseFloat(v, 32)
+		return float32(n), err
+	})
+	envstruct.RegisterFormatter(func(v float32) string {
+		if v == 0.5 {
+			return "half"
+		}
+
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	})
+
+	var generated Config
+	if err := envstruct.Load(&generated); err != nil {
+		t.Fatalf("Load with generated methods: %s", err)
+	}
+
+	if generated.Ratio != 0.5 {
+		t.Errorf("loaded ratio %v, want 0.5", generated.Ratio)
+	}
+
+	if env := envstruct.ToEnv(&generated); !slices.Contains(env, "RATIO=half") {
+		t.Errorf("ToEnv returned %q, want RATIO=half", env)
+	}
+}
+
+func TestGeneratedMethodsReturnTheSameErrors(t *testing.T) {
+	tests := map[string]map[string]string{
+		"missing":                 {"NAME": "", "PRIMARY_HOST": ""},
+		"invalid int":             {"COUNT": "300"},
+		"invalid uint":            {"PRIMARY_PORT": "-1"},
+		"invalid enum":            {"LEVEL": "trace"},
+		"invalid nested optional": {"REPLICA_PORT": "x"},
+	}
+
+	for name, env := range tests {
+		t.Run(name, func(t *testing.T) {
+			setenv(t, validEnv)
+			setenv(t, env)
+
+			got := envstruct.Load(&Config{})
+			want := envstruct.Load(&reflected{})
+
+			if got == nil || want == nil || got.Error() != want.Error() {
+				t.Errorf("Load returned %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+// Package gotypes describes, in terms of go/types, how envstruct loads the
+// fields of a struct. It is shared by the tools that work on Go source
+// rather than on values, so that they agree with envstruct.Load.
+package gotypes
+
+import (
+	"go/types"
+	"reflect"
+	"sort"
+
+	"code.cloudfoundry.org/go-envstruct/internal/tags"
+)
+
+// EnvstructPath is the import path of envstruct.
+const EnvstructPath = "code.cloudfoundry.org/go-envstruct"
+
+// builtinTypes are the types envstruct loads without an UnmarshalEnv
+// method, other than those of a supported kind.
+var builtinTypes = map[string]bool{
+	"time.Duration":              true,
+	"time.Time":                  true,
+	"*time.Location":             true,
+	"*net/url.URL":               true,
+	"net.IP":                     true,
+	"net.IPNet":                  true,
+	"*net.IPNet":                 true,
+	"net.HardwareAddr":           true,
+	"net/netip.Addr":             true,
+	"net/netip.AddrPort":         true,
+	"net/netip.Prefix":           true,
+	"*crypto/x509.Certificate":   true,
+	"[]*crypto/x509.Certificate": true,
+	"*crypto/x509.CertPool":      true,
+	"crypto.PrivateKey":          true,
+	"*regexp.Regexp":             true,
+	"io/fs.FileMode":             true,
+	EnvstructPath + ".Glob":      true,
+	EnvstructPath + ".ByteSize":  true,
+}
+
+// BuiltinTypes returns the types envstruct loads without an UnmarshalEnv
+// method, other than those of a supported kind, as formatted by
+// types.TypeString without a qualifier.
+func BuiltinTypes() []string {
+	names := make([]string, 0, len(builtinTypes))
+	for name := range builtinTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Rules decide how the fields of a struct are loaded.
+type Rules struct {
+	// Registered are the types, as formatted by types.TypeString without a
+	// qualifier, that have a parser registered with envstruct.RegisterParser.
+	Registered map[string]bool
+}
+
+// NewRules returns Rules for a package with type information info. Types
+// passed to envstruct.RegisterParser in the package and types listed in
+// extra are registered.
+func NewRules(info *types.Info, extra []string) *Rules {
+	r := &Rules{Registered: map[string]bool{}}
+
+	for _, t := range extra {
+		r.Registered[t] = true
+	}
+
+	if info == nil {
+		return r
+	}
+
+	for ident, inst := range info.Instances {
+		fn, ok := info.Uses[ident].(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != EnvstructPath || fn.Name() != "RegisterParser" {
+			continue
+		}
+
+		r.Registered[types.TypeString(inst.TypeArgs.At(0), nil)] = true
+	}
+
+	return r
+}
+
+// Field is a field with an `env` tag as visited by envstruct.Load.
+type Field struct {
+	// Var is the field.
+	Var *types.Var
+
+	// Tag is the struct tag of the field.
+	Tag reflect.StructTag
+
+	// Path is the path to the field from the root struct.
+	Path string
+
+	// EnvVar is the environment variable including any `envprefix`.
+	EnvVar string
+
+	// Options are the options of the `env` tag.
+	Options []string
+}
+
+// StructFields returns the fields of st that are loaded from an environment
+// variable, including those of nested structs.
+func (r *Rules) StructFields(st *types.Struct, path string) []Field {
+	var fields []Field
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		fields = append(fields, r.Fields(f, st.Tag(i), path+"."+f.Name())...)
+	}
+
+	return fields
+}
+
+// Fields returns the fields loaded from an environment variable for the
+// field v with struct tag tag: v itself if it has an `env` tag, or the
+// fields of the struct it refers to.
+func (r *Rules) Fields(v *types.Var, tag string, path string) []Field {
+	return r.fields(v, tag, "", path, map[*types.Struct]bool{})
+}
+
+func (r *Rules) fields(v *types.Var, tag, prefix, path string, visiting map[*types.Struct]bool) []Field {
+	structTag := reflect.StructTag(tag)
+	properties := tags.Split(structTag.Get(tags.Env))
+
+	if name := properties[0]; name != "" {
+		if !v.Exported() {
+			return nil
+		}
+
+		return []Field{{
+			Var:     v,
+			Tag:     structTag,
+			Path:    path,
+			EnvVar:  prefix + name,
+			Options: properties[1:],
+		}}
+	}
+
+	if !r.IsNested(v.Type()) {
+		return nil
+	}
+
+	st := NestedStruct(v.Type())
+	if visiting[st] {
+		return nil
+	}
+	visiting[st] = true
+	defer delete(visiting, st)
+
+	prefix += structTag.Get(tags.EnvPrefix)
+
+	var fields []Field
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		fields = append(fields, r.fields(f, st.Tag(i), prefix, path+"."+f.Name(), visiting)...)
+	}
+
+	return fields
+}
+
+// IsNested returns true if t is a struct, or a pointer to a struct, whose
+// fields are loaded individually.
+func (r *Rules) IsNested(t types.Type) bool {
+	return NestedStruct(t) != nil && !r.IsLeaf(t)
+}
+
+// NestedStruct returns the struct t or the struct t points to, nil if t is
+// neither.
+func NestedStruct(t types.Type) *types.Struct {
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		t = p.Elem()
+	}
+
+	st, _ := t.Underlying().(*types.Struct)
+	return st
+}
+
+// IsLeaf returns true if t is loaded from a single environment variable by
+// an UnmarshalEnv method, a built-in parser or a registered parser.
+func (r *Rules) IsLeaf(t types.Type) bool {
+	name := types.TypeString(t, nil)
+	if builtinTypes[name] || r.Registered[name] {
+		return true
+	}
+
+	if hasUnmarshalEnv(t) {
+		return true
+	}
+
+	if p, ok := t.(*types.Pointer); ok {
+		return hasUnmarshalEnv(p.Elem())
+	}
+
+	return false
+}
+
+// Supported returns true if envstruct can load a field of type t.
+func (r *Rules) Supported(t types.Type) bool {
+	if r.IsLeaf(t) {
+		return true
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return u.Info()&(types.IsBoolean|types.IsInteger|types.IsFloat|types.IsComplex|types.IsString) != 0 &&
+			u.Kind() != types.UnsafePointer
+	case *types.Slice:
+		return r.Supported(u.Elem())
+	case *types.Array:
+		return r.Supported(u.Elem())
+	case *types.Map:
+		return r.Supported(u.Key()) && r.Supported(u.Elem())
+	case *types.Pointer:
+		if _, ok := u.Elem().Underlying().(*types.Struct); ok {
+			return true
+		}
+
+		return r.Supported(u.Elem())
+	case *types.Interface:
+		return true
+	}
+
+	return false
+}
+
+// IsSecret returns true if t is an envstruct.Secret.
+func IsSecret(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Origin().Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == EnvstructPath && obj.Name() == "Secret"
+}
+
+// OptionType describes a field of type t for tags.Check.
+func OptionType(t types.Type) tags.Type {
+	_, pointer := t.Underlying().(*types.Pointer)
+	elem := elemType(t)
+	name := types.TypeString(elem, nil)
+
+	return tags.Type{
+		Pointer:  pointer,
+		Time:     name == "time.Time",
+		Duration: name == "time.Duration",
+		Bytes:    isBytes(elem),
+	}
+}
+
+// elemType returns the type of the values of a field of type t, looking
+// through pointers, envstruct.Secret and, unless t holds bytes, slices and
+// arrays.
+func elemType(t types.Type) types.Type {
+	for {
+		if IsSecret(t) {
+			t = t.(*types.Named).TypeArgs().At(0)
+			continue
+		}
+
+		switch u := t.Underlying().(type) {
+		case *types.Pointer:
+			t = u.Elem()
+		case *types.Slice:
+			if isBytes(t) {
+				return t
+			}
+			t = u.Elem()
+		case *types.Array:
+			if isBytes(t) {
+				return t
+			}
+			t = u.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// isBytes returns true for []byte and [N]byte types.
+func isBytes(t types.Type) bool {
+	var elem types.Type
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		elem = u.Elem()
+	case *types.Array:
+		elem = u.Elem()
+	default:
+		return false
+	}
+
+	return types.Identical(elem, types.Typ[types.Byte])
+}
+
+// hasUnmarshalEnv returns true if t or *t has an UnmarshalEnv(string) error
+// method.
+func hasUnmarshalEnv(t types.Type) bool {
+	if _, ok := t.(*types.Pointer); !ok {
+		t = types.NewPointer(t)
+	}
+
+	obj, _, _ := types.LookupFieldOrMethod(t, false, nil, "UnmarshalEnv")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig := fn.Type().(*types.Signature)
+	return sig.Params().Len() == 1 &&
+		types.Identical(sig.Params().At(0).Type(), types.Typ[types.String]) &&
+		sig.Results().Len() == 1 &&
+		types.Identical(sig.Results().At(0).Type(), types.Universe.Lookup("error").Type())
+}
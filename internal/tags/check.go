@@ -0,0 +1,169 @@
+package tags
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Values of the Encoding option.
+const (
+	EncodingBase64    = "base64"
+	EncodingBase64URL = "base64url"
+	EncodingHex       = "hex"
+)
+
+// Type describes the type of a field as far as the options of its `env` tag
+// are concerned. The values of a field are found by looking through
+// pointers, envstruct.Secret and, unless they hold bytes, slices and arrays.
+type Type struct {
+	// Pointer is true if the field is a pointer.
+	Pointer bool
+
+	// Time is true if the values are time.Time.
+	Time bool
+
+	// Duration is true if the values are time.Duration.
+	Duration bool
+
+	// Bytes is true if the values are []byte or [N]byte.
+	Bytes bool
+}
+
+// OptionError describes a problem with the options of an `env` tag.
+type OptionError struct {
+	// Options are the options with the problem, more than one if they
+	// conflict.
+	Options []string
+
+	// Problem describes the problem, e.g. `requires a value`. It is empty if
+	// the option is unknown.
+	Problem string
+}
+
+func (e *OptionError) Error() string {
+	return e.Message("")
+}
+
+// Message returns the message of e naming the field, as in
+// `env tag option "mask" on field Token requires a value`, or the message
+// without a field if field is empty.
+func (e *OptionError) Message(field string) string {
+	quoted := make([]string, len(e.Options))
+	for i, option := range e.Options {
+		quoted[i] = strconv.Quote(option)
+	}
+
+	var msg string
+	switch {
+	case e.Problem == "":
+		msg = "unknown env tag option " + quoted[0]
+	case len(quoted) == 1:
+		msg = "env tag option " + quoted[0]
+	default:
+		last := len(quoted) - 1
+		msg = "env tag options " + strings.Join(quoted[:last], ", ") + " and " + quoted[last]
+	}
+
+	if field != "" {
+		msg += " on field " + field
+	}
+
+	if e.Problem != "" {
+		msg += " " + e.Problem
+	}
+
+	return msg
+}
+
+// Check returns an error for every unknown, malformed or conflicting option
+// of the `env` tag of a field of type t.
+func Check(t Type, options []string) []*OptionError {
+	var errs []*OptionError
+	fail := func(problem string, options ...string) {
+		errs = append(errs, &OptionError{Options: options, Problem: problem})
+	}
+
+	redactions := 0
+	for _, option := range options {
+		if option == "" {
+			continue
+		}
+
+		key, value, hasValue := strings.Cut(option, "=")
+
+		takesValue, known := Options[key]
+		switch {
+		case !known:
+			fail("", key)
+			continue
+		case takesValue && value == "":
+			fail("requires a value", key)
+		case !takesValue && hasValue:
+			fail("does not take a value", key)
+		case takesValue && !validValue(key, value):
+			fail(fmt.Sprintf("has invalid value %q", value), key)
+		}
+
+		switch key {
+		case Mask, Hash, RedactUserinfo:
+			redactions++
+		case Optional:
+			if contains(options, Required) {
+				fail("conflict", Required, Optional)
+			}
+
+			if !t.Pointer {
+				fail("only applies to pointers", key)
+			}
+		case Layout:
+			if !t.Time {
+				fail("only applies to time.Time", key)
+			}
+		case Extended:
+			if !t.Duration {
+				fail("only applies to time.Duration", key)
+			}
+		case Encoding:
+			if !t.Bytes {
+				fail("only applies to []byte and [N]byte", key)
+			}
+		}
+	}
+
+	if redactions > 1 {
+		fail("conflict", Mask, Hash, RedactUserinfo)
+	}
+
+	return errs
+}
+
+// validValue returns true if value is a valid, non-empty value of the
+// option key.
+func validValue(key, value string) bool {
+	switch key {
+	case Encoding:
+		return value == EncodingBase64 || value == EncodingBase64URL || value == EncodingHex
+	case Mask:
+		n, err := strconv.Atoi(strings.TrimPrefix(value, "last"))
+		return strings.HasPrefix(value, "last") && err == nil && n >= 0
+	case Enum:
+		for _, v := range strings.Split(value, "|") {
+			if v == "" {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func contains(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
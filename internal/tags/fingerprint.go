@@ -0,0 +1,26 @@
+package tags
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strings"
+)
+
+// Tagged returns true if tag has an `env` or `envprefix` key.
+func Tagged(tag reflect.StructTag) bool {
+	_, env := tag.Lookup(Env)
+	_, prefix := tag.Lookup(EnvPrefix)
+
+	return env || prefix
+}
+
+// Fingerprint returns a short hash of the tagged fields of a struct. Each
+// field is given as its path from the struct, e.g. `.Database.Host`,
+// followed by a space and its struct tag. It is used to detect methods
+// generated by envstructgen for an older version of a struct.
+func Fingerprint(fields []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\n")))
+
+	return hex.EncodeToString(sum[:8])
+}
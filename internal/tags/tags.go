@@ -0,0 +1,81 @@
+// Package tags defines the vocabulary of the struct tags understood by
+// envstruct. It is shared by envstruct and the envstructlint analyzer so
+// that both agree on which options exist.
+package tags
+
+import "strings"
+
+// Struct tag keys.
+const (
+	Env         = "env"
+	EnvPrefix   = "envprefix"
+	Description = "description"
+)
+
+// Options of the `env` struct tag.
+const (
+	Required       = "required"
+	Report         = "report"
+	Mask           = "mask"
+	Hash           = "hash"
+	RedactUserinfo = "redact-userinfo"
+	Layout         = "layout"
+	Extended       = "extended"
+	Encoding       = "encoding"
+	Optional       = "optional"
+	Enum           = "enum"
+)
+
+// Options maps every option of the `env` struct tag to whether it takes a
+// value, as in `mask=last4`.
+var Options = map[string]bool{
+	Required:       false,
+	Report:         false,
+	Mask:           true,
+	Hash:           false,
+	RedactUserinfo: false,
+	Layout:         true,
+	Extended:       false,
+	Encoding:       true,
+	Optional:       false,
+	Enum:           true,
+}
+
+// Split splits the value of an `env` struct tag into its properties. The
+// first property is the name of the environment variable, the rest are
+// options. An option value may be quoted with single quotes to contain
+// commas, as in `layout='Mon, 02 Jan 2006'`; the quotes are removed.
+func Split(tag string) []string {
+	var properties []string
+
+	quoted := false
+	start := 0
+	for i := 0; i < len(tag); i++ {
+		switch tag[i] {
+		case '\'':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				properties = append(properties, tag[start:i])
+				start = i + 1
+			}
+		}
+	}
+	properties = append(properties, tag[start:])
+
+	for i, v := range properties {
+		properties[i] = unquote(strings.TrimSpace(v))
+	}
+
+	return properties
+}
+
+// unquote removes the single quotes around the value of an option.
+func unquote(property string) string {
+	name, value, ok := strings.Cut(property, "=")
+	if !ok || len(value) < 2 || value[0] != '\'' || value[len(value)-1] != '\'' {
+		return property
+	}
+
+	return name + "=" + value[1:len(value)-1]
+}
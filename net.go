@@ -0,0 +1,94 @@
+package envstruct
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"reflect"
+)
+
+func setIP(value reflect.Value, input string) error {
+	ip := net.ParseIP(input)
+	if ip == nil {
+		return fmt.Errorf("invalid IP address %q", input)
+	}
+
+	value.Set(reflect.ValueOf(ip))
+
+	return nil
+}
+
+func setIPNet(value reflect.Value, input string) error {
+	_, n, err := net.ParseCIDR(input)
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.ValueOf(*n))
+
+	return nil
+}
+
+func setIPNetPointer(value reflect.Value, input string) error {
+	_, n, err := net.ParseCIDR(input)
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.ValueOf(n))
+
+	return nil
+}
+
+func setHardwareAddr(value reflect.Value, input string) error {
+	addr, err := net.ParseMAC(input)
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.ValueOf(addr))
+
+	return nil
+}
+
+func setAddr(value reflect.Value, input string) error {
+	addr, err := netip.ParseAddr(input)
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.ValueOf(addr))
+
+	return nil
+}
+
+func setAddrPort(value reflect.Value, input string) error {
+	addrPort, err := netip.ParseAddrPort(input)
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.ValueOf(addrPort))
+
+	return nil
+}
+
+func setPrefix(value reflect.Value, input string) error {
+	prefix, err := netip.ParsePrefix(input)
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.ValueOf(prefix))
+
+	return nil
+}
+
+func formatIPNet(value reflect.Value, _ []string) string {
+	if !value.CanInterface() {
+		return omittedValue
+	}
+
+	n := value.Interface().(net.IPNet)
+	return n.String()
+}
@@ -0,0 +1,152 @@
+package envstruct_test
+
+import (
+	"bytes"
+	"net"
+	"net/netip"
+	"os"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type NetTestStruct struct {
+	IP           net.IP           `env:"NET_IP,report"`
+	IPNet        net.IPNet        `env:"NET_IPNET,report"`
+	IPNetPointer *net.IPNet       `env:"NET_IPNET_POINTER,report"`
+	HardwareAddr net.HardwareAddr `env:"NET_HARDWARE_ADDR,report"`
+	Addr         netip.Addr       `env:"NET_ADDR,report"`
+	AddrPort     netip.AddrPort   `env:"NET_ADDR_PORT,report"`
+	Prefix       netip.Prefix     `env:"NET_PREFIX,report"`
+	AllowList    []netip.Prefix   `env:"NET_ALLOW_LIST,report"`
+	IPs          []net.IP         `env:"NET_IPS,report"`
+}
+
+var netEnvVars = map[string]string{
+	"NET_IP":            "10.0.0.1",
+	"NET_IPNET":         "10.0.0.0/8",
+	"NET_IPNET_POINTER": "192.168.0.0/16",
+	"NET_HARDWARE_ADDR": "00:00:5e:00:53:01",
+	"NET_ADDR":          "2001:db8::1",
+	"NET_ADDR_PORT":     "127.0.0.1:8080",
+	"NET_PREFIX":        "fd00::/8",
+	"NET_ALLOW_LIST":    "10.0.0.0/8, 172.16.0.0/12",
+	"NET_IPS":           "10.0.0.1,10.0.0.2",
+}
+
+var _ = Describe("net types", func() {
+	var ts NetTestStruct
+
+	BeforeEach(func() {
+		for k, v := range netEnvVars {
+			os.Setenv(k, v)
+		}
+
+		ts = NetTestStruct{}
+	})
+
+	AfterEach(func() {
+		for k := range netEnvVars {
+			os.Unsetenv(k)
+		}
+	})
+
+	It("parses the values", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(ts.IP.String()).To(Equal("10.0.0.1"))
+		Expect(ts.IPNet.String()).To(Equal("10.0.0.0/8"))
+		Expect(ts.IPNetPointer.String()).To(Equal("192.168.0.0/16"))
+		Expect(ts.HardwareAddr.String()).To(Equal("00:00:5e:00:53:01"))
+		Expect(ts.Addr).To(Equal(netip.MustParseAddr("2001:db8::1")))
+		Expect(ts.AddrPort).To(Equal(netip.MustParseAddrPort("127.0.0.1:8080")))
+		Expect(ts.Prefix).To(Equal(netip.MustParsePrefix("fd00::/8")))
+		Expect(ts.AllowList).To(Equal([]netip.Prefix{
+			netip.MustParsePrefix("10.0.0.0/8"),
+			netip.MustParsePrefix("172.16.0.0/12"),
+		}))
+		Expect(ts.IPs).To(HaveLen(2))
+		Expect(ts.IPs[1].String()).To(Equal("10.0.0.2"))
+	})
+
+	It("keeps the default value when the env var is empty", func() {
+		os.Setenv("NET_ADDR", "")
+		ts.Addr = netip.MustParseAddr("127.0.0.1")
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+		Expect(ts.Addr).To(Equal(netip.MustParseAddr("127.0.0.1")))
+	})
+
+	DescribeTable("returns an error for invalid values",
+		func(envVar string) {
+			os.Setenv(envVar, "invalid")
+
+			Expect(envstruct.Load(&ts)).ToNot(Succeed())
+		},
+		Entry("net.IP", "NET_IP"),
+		Entry("net.IPNet", "NET_IPNET"),
+		Entry("*net.IPNet", "NET_IPNET_POINTER"),
+		Entry("net.HardwareAddr", "NET_HARDWARE_ADDR"),
+		Entry("netip.Addr", "NET_ADDR"),
+		Entry("netip.AddrPort", "NET_ADDR_PORT"),
+		Entry("netip.Prefix", "NET_PREFIX"),
+		Entry("[]netip.Prefix", "NET_ALLOW_LIST"),
+	)
+
+	It("formats the values with ToEnv", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(envstruct.ToEnv(&ts)).To(ConsistOf(
+			"NET_IP=10.0.0.1",
+			"NET_IPNET=10.0.0.0/8",
+			"NET_IPNET_POINTER=192.168.0.0/16",
+			"NET_HARDWARE_ADDR=00:00:5e:00:53:01",
+			"NET_ADDR=2001:db8::1",
+			"NET_ADDR_PORT=127.0.0.1:8080",
+			"NET_PREFIX=fd00::/8",
+			"NET_ALLOW_LIST=10.0.0.0/8,172.16.0.0/12",
+			"NET_IPS=10.0.0.1,10.0.0.2",
+		))
+	})
+
+	It("displays the values in the report", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		outputBuffer := bytes.NewBuffer(nil)
+		envstruct.ReportWriter = outputBuffer
+
+		Expect(envstruct.WriteReport(&ts)).To(Succeed())
+		Expect(outputBuffer.String()).To(Equal(expectedNetReportOutput))
+	})
+
+	It("does not panic on unexported fields", func() {
+		_, ipNet, err := net.ParseCIDR("10.0.0.0/8")
+		Expect(err).ToNot(HaveOccurred())
+
+		s := struct {
+			ipNet        net.IPNet  `env:"NET_IPNET,report"`
+			ipNetPointer *net.IPNet `env:"NET_IPNET_POINTER,report"`
+		}{*ipNet, ipNet}
+
+		envstruct.ReportWriter = bytes.NewBuffer(nil)
+
+		Expect(func() { envstruct.ToEnv(&s) }).ToNot(Panic())
+		Expect(func() { envstruct.WriteReport(&s) }).ToNot(Panic())
+	})
+})
+
+const (
+	expectedNetReportOutput = `FIELD NAME:                 TYPE:             ENV:               REQUIRED:  VALUE:
+NetTestStruct.IP            net.IP            NET_IP             false      10.0.0.1
+NetTestStruct.IPNet         net.IPNet         NET_IPNET          false      10.0.0.0/8
+NetTestStruct.IPNetPointer  *net.IPNet        NET_IPNET_POINTER  false      192.168.0.0/16
+NetTestStruct.HardwareAddr  net.HardwareAddr  NET_HARDWARE_ADDR  false      00:00:5e:00:53:01
+NetTestStruct.Addr          netip.Addr        NET_ADDR           false      2001:db8::1
+NetTestStruct.AddrPort      netip.AddrPort    NET_ADDR_PORT      false      127.0.0.1:8080
+NetTestStruct.Prefix        netip.Prefix      NET_PREFIX         false      fd00::/8
+NetTestStruct.AllowList     []netip.Prefix    NET_ALLOW_LIST     false      [10.0.0.0/8 172.16.0.0/12]
+NetTestStruct.IPs           []net.IP          NET_IPS            false      [10.0.0.1 10.0.0.2]
+`
+)
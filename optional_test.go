@@ -0,0 +1,144 @@
+package envstruct_test
+
+import (
+	"bytes"
+	"crypto/x509"
+	"net/url"
+	"os"
+	"regexp"
+	"time"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type OptionalDBConfig struct {
+	Host     string `env:"OPTIONAL_DB_HOST,required,report"`
+	Password string `env:"OPTIONAL_DB_PASSWORD"`
+}
+
+type OptionalNode struct {
+	Name string        `env:"OPTIONAL_NODE_NAME,report"`
+	Next *OptionalNode `env:",optional" envprefix:"NEXT_"`
+}
+
+type OptionalTestStruct struct {
+	DB      *OptionalDBConfig `env:",optional"`
+	Cache   *OptionalDBConfig `envprefix:"CACHE_"`
+	Port    *int              `env:"OPTIONAL_PORT,optional,report"`
+	Timeout *int              `env:"OPTIONAL_TIMEOUT,report"`
+	Node    OptionalNode
+}
+
+type NilPointerTestStruct struct {
+	URL      *url.URL          `env:"NIL_URL,report"`
+	Location *time.Location    `env:"NIL_LOCATION,report"`
+	Regexp   *regexp.Regexp    `env:"NIL_REGEXP,report"`
+	Cert     *x509.Certificate `env:"NIL_CERT,report"`
+	Int      *int              `env:"NIL_INT,report"`
+	DB       *OptionalDBConfig
+}
+
+var _ = Describe("optional pointers", func() {
+	var ts OptionalTestStruct
+
+	BeforeEach(func() {
+		ts = OptionalTestStruct{}
+		os.Setenv("CACHE_OPTIONAL_DB_HOST", "cache")
+	})
+
+	AfterEach(func() {
+		for _, k := range []string{
+			"CACHE_OPTIONAL_DB_HOST",
+			"OPTIONAL_DB_HOST",
+			"OPTIONAL_DB_PASSWORD",
+			"OPTIONAL_PORT",
+			"NEXT_OPTIONAL_NODE_NAME",
+		} {
+			os.Unsetenv(k)
+		}
+	})
+
+	It("leaves pointers nil when none of their variables are set", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(ts.DB).To(BeNil())
+		Expect(ts.Port).To(BeNil())
+		Expect(ts.Node.Next).To(BeNil())
+	})
+
+	It("allocates pointers that are not optional", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(ts.Cache).ToNot(BeNil())
+		Expect(ts.Timeout).ToNot(BeNil())
+	})
+
+	It("allocates optional pointers when a variable is set", func() {
+		os.Setenv("OPTIONAL_DB_PASSWORD", "secret")
+		os.Setenv("OPTIONAL_PORT", "8080")
+		os.Setenv("NEXT_OPTIONAL_NODE_NAME", "next")
+
+		err := envstruct.Load(&ts)
+		Expect(err).To(MatchError("missing required environment variables: OPTIONAL_DB_HOST"))
+
+		os.Setenv("OPTIONAL_DB_HOST", "db")
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(ts.DB).To(Equal(&OptionalDBConfig{Host: "db", Password: "secret"}))
+		Expect(*ts.Port).To(Equal(8080))
+		Expect(ts.Node.Next.Name).To(Equal("next"))
+		Expect(ts.Node.Next.Next).To(BeNil())
+	})
+
+	It("does not include nil pointers in ToEnv", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(envstruct.ToEnv(&ts)).To(ConsistOf(
+			"CACHE_OPTIONAL_DB_HOST=cache",
+			"CACHE_OPTIONAL_DB_PASSWORD=",
+			"OPTIONAL_TIMEOUT=0",
+			"OPTIONAL_NODE_NAME=",
+		))
+	})
+
+	It("reports the fields of nil pointers", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		entries, err := envstruct.Report(&ts)
+		Expect(err).ToNot(HaveOccurred())
+
+		var envVars []string
+		for _, e := range entries {
+			envVars = append(envVars, e.EnvVar)
+		}
+
+		Expect(envVars).To(Equal([]string{
+			"OPTIONAL_DB_HOST",
+			"OPTIONAL_DB_PASSWORD",
+			"CACHE_OPTIONAL_DB_HOST",
+			"CACHE_OPTIONAL_DB_PASSWORD",
+			"OPTIONAL_PORT",
+			"OPTIONAL_TIMEOUT",
+			"OPTIONAL_NODE_NAME",
+		}))
+	})
+
+	It("handles nil pointers of every type", func() {
+		ts := NilPointerTestStruct{}
+
+		Expect(envstruct.ToEnv(&ts)).To(BeEmpty())
+
+		var buf bytes.Buffer
+		envstruct.ReportWriter = &buf
+		defer func() { envstruct.ReportWriter = os.Stderr }()
+
+		Expect(envstruct.WriteReport(&ts)).To(Succeed())
+		Expect(envstruct.WriteUsage(&buf, &ts)).To(Succeed())
+		Expect(envstruct.LogValue(&ts).String()).ToNot(ContainSubstring("ERROR"))
+		Expect(buf.String()).To(ContainSubstring("NIL_CERT"))
+		Expect(buf.String()).To(ContainSubstring("OPTIONAL_DB_HOST"))
+	})
+})
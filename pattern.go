@@ -0,0 +1,85 @@
+package envstruct
+
+import (
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var regexpType = reflect.TypeOf(&regexp.Regexp{})
+
+// Glob is a shell file name pattern, as understood by path.Match. Load
+// returns an error if the pattern is malformed.
+type Glob string
+
+// Match reports whether name matches the pattern.
+func (g Glob) Match(name string) bool {
+	ok, _ := path.Match(string(g), name)
+	return ok
+}
+
+func setRegexp(value reflect.Value, input string) error {
+	re, err := regexp.Compile(input)
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.ValueOf(re))
+
+	return nil
+}
+
+// separatePatterns splits a list of regular expressions on the commas that
+// are outside of brackets, braces and parentheses and not escaped with a
+// backslash, so that e.g. `\d{2,4},[,;]` holds two patterns. A literal comma
+// is written as `\,`, which the regexp package reads as a comma.
+func separatePatterns(input string) []string {
+	var (
+		patterns []string
+		start    int
+		depth    int
+		inClass  bool
+	)
+
+	for i := 0; i < len(input); i++ {
+		switch c := input[i]; {
+		case c == '\\':
+			i++
+		case inClass && c == '[' && strings.HasPrefix(input[i:], "[:"):
+			if end := strings.Index(input[i+2:], ":]"); end >= 0 {
+				i += end + 3
+			}
+		case inClass:
+			inClass = c != ']'
+		case c == '[':
+			inClass = true
+			// A ] right after the opening [ or [^ is part of the class.
+			if strings.HasPrefix(input[i+1:], "^") {
+				i++
+			}
+			if strings.HasPrefix(input[i+1:], "]") {
+				i++
+			}
+		case c == '(' || c == '{':
+			depth++
+		case (c == ')' || c == '}') && depth > 0:
+			depth--
+		case c == ',' && depth == 0:
+			patterns = append(patterns, strings.TrimSpace(input[start:i]))
+			start = i + 1
+		}
+	}
+
+	return append(patterns, strings.TrimSpace(input[start:]))
+}
+
+func setGlob(value reflect.Value, input string) error {
+	if _, err := path.Match(input, ""); err != nil {
+		return err
+	}
+
+	value.SetString(input)
+
+	return nil
+}
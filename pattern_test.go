@@ -0,0 +1,121 @@
+package envstruct_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"regexp"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type PatternTestStruct struct {
+	Route   *regexp.Regexp   `env:"PATTERN_ROUTE,report"`
+	Filters []*regexp.Regexp `env:"PATTERN_FILTERS,report"`
+	Files   envstruct.Glob   `env:"PATTERN_FILES,report"`
+}
+
+var patternEnvVars = map[string]string{
+	"PATTERN_ROUTE":   `^/v[0-9]+/apps/(?P<guid>[a-f0-9-]+)$`,
+	"PATTERN_FILTERS": `^debug\.,\.tmp$`,
+	"PATTERN_FILES":   "*.log",
+}
+
+var _ = Describe("pattern types", func() {
+	var ts PatternTestStruct
+
+	BeforeEach(func() {
+		for k, v := range patternEnvVars {
+			os.Setenv(k, v)
+		}
+
+		ts = PatternTestStruct{}
+	})
+
+	AfterEach(func() {
+		for k := range patternEnvVars {
+			os.Unsetenv(k)
+		}
+	})
+
+	It("compiles the values", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(ts.Route.MatchString("/v3/apps/abc-123")).To(BeTrue())
+		Expect(ts.Route.SubexpNames()).To(ContainElement("guid"))
+		Expect(ts.Filters).To(HaveLen(2))
+		Expect(ts.Filters[0].MatchString("debug.log")).To(BeTrue())
+		Expect(ts.Filters[1].MatchString("file.tmp")).To(BeTrue())
+		Expect(ts.Files.Match("app.log")).To(BeTrue())
+		Expect(ts.Files.Match("app.txt")).To(BeFalse())
+	})
+
+	It("returns a parse error for an invalid regular expression", func() {
+		os.Setenv("PATTERN_ROUTE", "(unclosed")
+
+		err := envstruct.Load(&ts)
+
+		var parseErr *envstruct.ParseError
+		Expect(errors.As(err, &parseErr)).To(BeTrue())
+		Expect(parseErr.EnvVar).To(Equal("PATTERN_ROUTE"))
+		Expect(err).To(MatchError("failed to parse PATTERN_ROUTE as *regexp.Regexp: error parsing regexp: missing closing ): `(unclosed`"))
+	})
+
+	It("only separates the patterns of a slice on commas outside of brackets, braces and parentheses", func() {
+		os.Setenv("PATTERN_FILTERS", `^\d{2,4}$, [,;], (a|,b), x\,y`)
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(ts.Filters).To(HaveLen(4))
+		Expect(ts.Filters[0].String()).To(Equal(`^\d{2,4}$`))
+		Expect(ts.Filters[0].MatchString("123")).To(BeTrue())
+		Expect(ts.Filters[1].MatchString(",")).To(BeTrue())
+		Expect(ts.Filters[2].MatchString(",b")).To(BeTrue())
+		Expect(ts.Filters[3].MatchString("x,y")).To(BeTrue())
+	})
+
+	It("writes slices of patterns with commas so that they load again", func() {
+		os.Setenv("PATTERN_FILTERS", `^\d{2,4}$,[,;]`)
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(envstruct.ToEnv(&ts)).To(ContainElement(`PATTERN_FILTERS=^\d{2,4}$,[,;]`))
+	})
+
+	It("returns a parse error for an invalid regular expression in a slice", func() {
+		os.Setenv("PATTERN_FILTERS", "valid,[invalid")
+
+		var parseErr *envstruct.ParseError
+		Expect(errors.As(envstruct.Load(&ts), &parseErr)).To(BeTrue())
+		Expect(parseErr.EnvVar).To(Equal("PATTERN_FILTERS"))
+	})
+
+	It("returns a parse error for an invalid glob", func() {
+		os.Setenv("PATTERN_FILES", "[invalid")
+
+		Expect(envstruct.Load(&ts)).To(MatchError("failed to parse PATTERN_FILES as envstruct.Glob: syntax error in pattern"))
+	})
+
+	It("writes the source patterns with ToEnv", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(envstruct.ToEnv(&ts)).To(ConsistOf(
+			"PATTERN_ROUTE="+patternEnvVars["PATTERN_ROUTE"],
+			"PATTERN_FILTERS="+patternEnvVars["PATTERN_FILTERS"],
+			"PATTERN_FILES=*.log",
+		))
+	})
+
+	It("displays the source patterns in the report", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		outputBuffer := bytes.NewBuffer(nil)
+		envstruct.ReportWriter = outputBuffer
+
+		Expect(envstruct.WriteReport(&ts)).To(Succeed())
+		Expect(outputBuffer.String()).To(ContainSubstring(patternEnvVars["PATTERN_ROUTE"]))
+		Expect(outputBuffer.String()).To(ContainSubstring(`[^debug\. \.tmp$]`))
+	})
+})
@@ -0,0 +1,152 @@
+package envstruct
+
+import (
+	"reflect"
+)
+
+// structPlan describes how the fields of a struct type are loaded with a
+// given prefix. Plans are built once per type and prefix and shared by Load,
+// ToEnv and WriteReport, so that struct tags are not parsed on every call.
+// Plans are read-only once built.
+type structPlan struct {
+	fields []fieldPlan
+}
+
+// fieldPlan describes a single field of a struct.
+type fieldPlan struct {
+	field reflect.StructField
+
+	// tagProperties are the properties of the `env` tag with the prefix
+	// added to the environment variable. They must not be modified.
+	tagProperties []string
+
+	// hasEnvTag is true if the `env` tag names an environment variable.
+	hasEnvTag bool
+
+	// required and optional are true if the `env` tag has these options.
+	required, optional bool
+
+	// enum are the values allowed by the `enum` option, nil if there is
+	// none.
+	enum []string
+
+	// list is true if the field is loaded from a comma separated list of
+	// elements.
+	list bool
+
+	// set parses the field if it has a built-in or registered parser and no
+	// UnmarshalEnv method, nil otherwise.
+	set typeSetter
+
+	// format formats the field for ToEnv if it has a built-in or registered
+	// parser or formatter, nil otherwise.
+	format typeFormatter
+
+	// nestedPrefix is the prefix of the fields of the struct the field
+	// refers to, if it has no `env` tag.
+	nestedPrefix string
+
+	// nestedType is the struct type the field refers to if it has no `env`
+	// tag and its fields are loaded individually, or nil.
+	nestedType reflect.Type
+}
+
+func (f *fieldPlan) envVar() string {
+	return f.tagProperties[indexEnvVar]
+}
+
+type planKey struct {
+	t      reflect.Type
+	prefix string
+}
+
+// planFor returns the plan of the struct type t loaded with prefix. Plans
+// are cached per planKey in the current registry, as registering a parser or
+// formatter changes which types are nested structs and how fields are parsed
+// and formatted.
+func planFor(t reflect.Type, prefix string) *structPlan {
+	plans := &registry.current.Load().plans
+
+	key := planKey{t: t, prefix: prefix}
+	if p, ok := plans.Load(key); ok {
+		return p.(*structPlan)
+	}
+
+	p := &structPlan{fields: make([]fieldPlan, t.NumField())}
+	for i := range p.fields {
+		p.fields[i] = newFieldPlan(t.Field(i), prefix)
+	}
+
+	actual, _ := plans.LoadOrStore(key, p)
+	return actual.(*structPlan)
+}
+
+type fieldPlanKey struct {
+	t      reflect.Type
+	tag    string
+	prefix string
+}
+
+// planForField returns the plan of a field of type t with struct tag tag
+// loaded with prefix, for fields loaded on their own by code generated by
+// envstructgen. Plans are cached per fieldPlanKey like those of planFor.
+func planForField(t reflect.Type, tag, prefix string) *fieldPlan {
+	fieldPlans := &registry.current.Load().fieldPlans
+
+	key := fieldPlanKey{t: t, tag: tag, prefix: prefix}
+	if f, ok := fieldPlans.Load(key); ok {
+		return f.(*fieldPlan)
+	}
+
+	f := newFieldPlan(reflect.StructField{Type: t, Tag: reflect.StructTag(tag)}, prefix)
+
+	actual, _ := fieldPlans.LoadOrStore(key, &f)
+	return actual.(*fieldPlan)
+}
+
+// newFieldPlan returns the plan of a field of a struct loaded with prefix.
+func newFieldPlan(field reflect.StructField, prefix string) fieldPlan {
+	f := valuePlan(field.Type, envTagProperties(field.Tag, prefix))
+	f.field = field
+
+	if !f.hasEnvTag {
+		f.nestedPrefix = prefix + field.Tag.Get(tagEnvPrefix)
+		f.nestedType, _ = nestedStructType(field.Type)
+	}
+
+	return f
+}
+
+// valuePlan returns the plan of a value of type t loaded with the properties
+// of an `env` tag.
+func valuePlan(t reflect.Type, tagProperties []string) fieldPlan {
+	f := fieldPlan{
+		field:         reflect.StructField{Type: t},
+		tagProperties: tagProperties,
+		hasEnvTag:     hasEnvTag(tagProperties),
+		required:      tagPropertiesContains(tagProperties, tagRequired),
+		optional:      tagPropertiesContains(tagProperties, tagOptional),
+		list:          isList(t),
+	}
+	f.enum, _ = enumValues(tagProperties)
+
+	if !isUnmarshaller(t) && t.Kind() != reflect.Pointer && t.Kind() != reflect.Interface {
+		f.set, _ = lookupSetter(t)
+	}
+
+	if hasTypeHandling(t) && !t.Implements(revealerType) && t != certPoolType {
+		var ok bool
+		if f.format, ok = lookupFormatter(t); !ok {
+			f.format = formatDefault
+		}
+	}
+
+	return f
+}
+
+// resetPlans discards all plans cached in the current registry.
+func resetPlans() {
+	r := registry.current.Load()
+	r.plans.Clear()
+	r.fieldPlans.Clear()
+}
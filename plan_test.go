@@ -0,0 +1,147 @@
+package envstruct_test
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type planLocation struct {
+	City string `env:"PLAN_CITY,report"`
+}
+
+// planRegistered gets a parser registered by the specs, which changes it
+// from a nested struct to a value loaded from a single environment variable.
+type planRegistered struct {
+	City string `env:"PLAN_CITY,report"`
+}
+
+type PlanTestStruct struct {
+	Name     string         `env:"PLAN_NAME,required,report"`
+	Location planRegistered `env:"PLAN_LOCATION"`
+	Home     planRegistered `envprefix:"HOME_"`
+}
+
+var _ = Describe("Plans", func() {
+	BeforeEach(func() {
+		Expect(os.Setenv("PLAN_NAME", "app")).To(Succeed())
+		Expect(os.Setenv("PLAN_CITY", "Berlin")).To(Succeed())
+		Expect(os.Setenv("HOME_PLAN_CITY", "Paris")).To(Succeed())
+		Expect(os.Setenv("PLAN_LOCATION", "Rome")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv("PLAN_NAME")).To(Succeed())
+		Expect(os.Unsetenv("PLAN_CITY")).To(Succeed())
+		Expect(os.Unsetenv("HOME_PLAN_CITY")).To(Succeed())
+		Expect(os.Unsetenv("PLAN_LOCATION")).To(Succeed())
+	})
+
+	It("loads the same struct concurrently", func() {
+		var wg sync.WaitGroup
+		results := make([]struct {
+			Name string
+			Home planLocation
+		}, 20)
+		errs := make([]error, len(results))
+
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				var s struct {
+					Name string       `env:"PLAN_NAME,required,report"`
+					Home planLocation `envprefix:"HOME_"`
+				}
+				errs[i] = envstruct.Load(&s)
+				results[i].Name, results[i].Home = s.Name, s.Home
+			}()
+		}
+		wg.Wait()
+
+		for i := range results {
+			Expect(errs[i]).ToNot(HaveOccurred())
+			Expect(results[i].Name).To(Equal("app"))
+			Expect(results[i].Home.City).To(Equal("Paris"))
+		}
+	})
+
+	It("keeps the plans of the same struct type with different prefixes apart", func() {
+		var s struct {
+			Work planLocation
+			Home planLocation `envprefix:"HOME_"`
+		}
+
+		Expect(envstruct.Load(&s)).To(Succeed())
+		Expect(s.Work.City).To(Equal("Berlin"))
+		Expect(s.Home.City).To(Equal("Paris"))
+	})
+
+	It("uses parsers registered after a struct was loaded", func() {
+		s := PlanTestStruct{}
+		Expect(envstruct.Load(&s)).To(MatchError(ContainSubstring("needs to have an UnmarshallEnv method")))
+
+		envstruct.RegisterParser(func(v string) (planRegistered, error) {
+			return planRegistered{City: v}, nil
+		})
+		defer envstruct.ResetRegistry()
+
+		Expect(envstruct.Load(&s)).To(Succeed())
+		Expect(s.Location.City).To(Equal("Rome"))
+		Expect(s.Home.City).To(BeEmpty())
+	})
+
+	It("uses parsers and formatters registered after a field was loaded", func() {
+		Expect(os.Setenv("PLAN_TIMEOUT", "5s")).To(Succeed())
+		defer os.Unsetenv("PLAN_TIMEOUT")
+
+		var s struct {
+			Timeout time.Duration `env:"PLAN_TIMEOUT"`
+		}
+		Expect(envstruct.Load(&s)).To(Succeed())
+		Expect(s.Timeout).To(Equal(5 * time.Second))
+
+		envstruct.RegisterParser(func(v string) (time.Duration, error) {
+			return time.Minute, nil
+		})
+		envstruct.RegisterFormatter(func(d time.Duration) string {
+			return "a minute"
+		})
+		defer envstruct.ResetRegistry()
+
+		Expect(envstruct.Load(&s)).To(Succeed())
+		Expect(s.Timeout).To(Equal(time.Minute))
+		Expect(envstruct.ToEnv(&s)).To(Equal([]string{"PLAN_TIMEOUT=a minute"}))
+	})
+	It("does not keep plans built while a parser is registered", func() {
+		defer envstruct.ResetRegistry()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for j := 0; j < 50; j++ {
+					s := PlanTestStruct{}
+					_ = envstruct.Load(&s)
+				}
+			}()
+		}
+
+		envstruct.RegisterParser(func(v string) (planRegistered, error) {
+			return planRegistered{City: v}, nil
+		})
+		wg.Wait()
+
+		s := PlanTestStruct{}
+		Expect(envstruct.Load(&s)).To(Succeed())
+		Expect(s.Location.City).To(Equal("Rome"))
+	})
+})
@@ -0,0 +1,167 @@
+package envstruct
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// reportValue returns the value of the field as it may be displayed in a
+// report. It returns false if the value must be omitted.
+func (f envField) reportValue() (string, bool) {
+	if tagPropertiesContains(f.tagProperties, tagHash) {
+		return hashValue(revealedString(f.value, f.tagProperties)), true
+	}
+
+	if mask, ok := tagPropertyValue(f.tagProperties, tagMask); ok {
+		return maskValue(revealedString(f.value, f.tagProperties), mask), true
+	}
+
+	if tagPropertiesContains(f.tagProperties, tagRedactUserinfo) {
+		return redactUserinfo(revealed(f.value)), true
+	}
+
+	if _, ok := typeSummaries[f.value.Type()]; ok && !matchesRedactPatterns(f) {
+		return displayString(f.value, f.tagProperties), true
+	}
+
+	if !f.reportsFullValue() {
+		return omittedValue, false
+	}
+
+	return displayString(f.value, f.tagProperties), true
+}
+
+// reportsFullValue returns true if the unredacted value of the field may be
+// displayed in a report.
+func (f envField) reportsFullValue() bool {
+	if !f.reported() || matchesRedactPatterns(f) {
+		return false
+	}
+
+	return !tagPropertiesContains(f.tagProperties, tagHash) &&
+		!tagPropertiesContains(f.tagProperties, tagRedactUserinfo) &&
+		!tagPropertiesHasKey(f.tagProperties, tagMask)
+}
+
+func matchesRedactPatterns(f envField) bool {
+	names := []string{
+		strings.ToUpper(f.envVar),
+		strings.ToUpper(f.field.Name),
+	}
+
+	for _, p := range f.redactPatterns {
+		for _, n := range names {
+			if ok, _ := path.Match(strings.ToUpper(p), n); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func revealed(v reflect.Value) reflect.Value {
+	if v.CanInterface() {
+		if r, ok := v.Interface().(revealer); ok {
+			return r.reveal()
+		}
+	}
+
+	return v
+}
+
+func revealedString(v reflect.Value, tagProperties []string) string {
+	return displayString(revealed(v), tagProperties)
+}
+
+// displayString formats v for a report. Types implementing fmt.Stringer
+// are displayed in their human readable form rather than the form ToEnv
+// writes.
+func displayString(v reflect.Value, tagProperties []string) string {
+	if summarize, ok := typeSummaries[v.Type()]; ok && v.CanInterface() {
+		return summarize(v)
+	}
+
+	if format, ok := registeredFormatter(v.Type()); ok && v.CanInterface() {
+		return format(v, tagProperties)
+	}
+
+	if v.CanInterface() {
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			return fmt.Sprint(s)
+		}
+	}
+
+	if format, ok := lookupFormatter(v.Type()); ok {
+		return format(v, tagProperties)
+	}
+
+	if v.Kind() == reflect.Slice {
+		if _, ok := lookupFormatter(v.Type().Elem()); ok {
+			parts := make([]string, v.Len())
+			for i := range parts {
+				parts[i] = displayString(v.Index(i), tagProperties)
+			}
+
+			return "[" + strings.Join(parts, " ") + "]"
+		}
+	}
+
+	return fmt.Sprint(v)
+}
+
+// hashValue returns a short SHA-256 fingerprint of s.
+func hashValue(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return "sha256:" + hex.EncodeToString(sum[:])[:12]
+}
+
+// maskValue masks s according to a `mask` tag value of the form `lastN`,
+// leaving only the last N characters visible.
+func maskValue(s, mask string) string {
+	n, err := strconv.Atoi(strings.TrimPrefix(mask, "last"))
+	if err != nil || !strings.HasPrefix(mask, "last") || n < 0 {
+		return omittedValue
+	}
+
+	r := []rune(s)
+	if len(r) <= n {
+		return "****"
+	}
+
+	return "****" + string(r[len(r)-n:])
+}
+
+func redactUserinfo(v reflect.Value) string {
+	if !v.CanInterface() {
+		return omittedValue
+	}
+
+	var u *url.URL
+	switch x := v.Interface().(type) {
+	case *url.URL:
+		u = x
+	case url.URL:
+		u = &x
+	case string:
+		parsed, err := url.Parse(x)
+		if err != nil {
+			return omittedValue
+		}
+		u = parsed
+	default:
+		return omittedValue
+	}
+
+	if u == nil {
+		return fmt.Sprint(u)
+	}
+
+	return u.Redacted()
+}
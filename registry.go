@@ -0,0 +1,107 @@
+package envstruct
+
+import (
+	"maps"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// registered holds the registered parsers and formatters. It is never
+// modified once it is stored in registry, so that looking up a type does not
+// need a lock.
+type registered struct {
+	setters    map[reflect.Type]typeSetter
+	formatters map[reflect.Type]typeFormatter
+
+	// plans and fieldPlans cache the plans built while r is the current
+	// registry. A registration stores a new registered with empty caches, so
+	// a plan built from an older registry is never used afterwards.
+	plans      sync.Map
+	fieldPlans sync.Map
+}
+
+var registry struct {
+	// mu serializes registrations.
+	mu sync.Mutex
+
+	current atomic.Pointer[registered]
+}
+
+func init() {
+	registry.current.Store(&registered{
+		setters:    map[reflect.Type]typeSetter{},
+		formatters: map[reflect.Type]typeFormatter{},
+	})
+}
+
+// register replaces the registered parsers and formatters with a copy
+// modified by update.
+func register(update func(r *registered)) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	current := registry.current.Load()
+	r := &registered{
+		setters:    maps.Clone(current.setters),
+		formatters: maps.Clone(current.formatters),
+	}
+	update(r)
+	registry.current.Store(r)
+}
+
+// RegisterParser registers a function that parses the value of an
+// environment variable into a T. This makes it possible to load types that
+// can not implement Unmarshaller, such as types from other packages.
+// Registered parsers take precedence over the parsing built into envstruct
+// and replace any parser previously registered for T.
+func RegisterParser[T any](parse func(string) (T, error)) {
+	register(func(r *registered) {
+		r.setters[typeOf[T]()] = func(value reflect.Value, input string, _ []string) error {
+			v, err := parse(input)
+			if err != nil {
+				return err
+			}
+
+			value.Set(reflect.ValueOf(&v).Elem())
+
+			return nil
+		}
+	})
+}
+
+// RegisterFormatter registers a function that formats a T for ToEnv and
+// reports. It should produce a value that the parser for T accepts.
+func RegisterFormatter[T any](format func(T) string) {
+	register(func(r *registered) {
+		r.formatters[typeOf[T]()] = func(value reflect.Value, _ []string) string {
+			if !value.CanInterface() {
+				return omittedValue
+			}
+
+			return format(value.Interface().(T))
+		}
+	})
+}
+
+func registeredSetter(t reflect.Type) (typeSetter, bool) {
+	set, ok := registry.current.Load().setters[t]
+	return set, ok
+}
+
+func registeredFormatter(t reflect.Type) (typeFormatter, bool) {
+	format, ok := registry.current.Load().formatters[t]
+	return format, ok
+}
+
+// resetRegistry removes all registered parsers and formatters.
+func resetRegistry() {
+	register(func(r *registered) {
+		clear(r.setters)
+		clear(r.formatters)
+	})
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,134 @@
+package envstruct_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type registryPoint struct {
+	X, Y int
+}
+
+type RegistryTestStruct struct {
+	Big    *big.Int        `env:"REGISTRY_BIG,report"`
+	Month  time.Month      `env:"REGISTRY_MONTH,report"`
+	Point  registryPoint   `env:"REGISTRY_POINT,report"`
+	Points []registryPoint `env:"REGISTRY_POINTS,report"`
+}
+
+var registryEnvVars = map[string]string{
+	"REGISTRY_BIG":    "123456789012345678901234567890",
+	"REGISTRY_MONTH":  "October",
+	"REGISTRY_POINT":  "1:2",
+	"REGISTRY_POINTS": "3:4,5:6",
+}
+
+var _ = Describe("Registry", func() {
+	var ts RegistryTestStruct
+
+	BeforeEach(func() {
+		envstruct.RegisterParser(func(s string) (*big.Int, error) {
+			n, ok := new(big.Int).SetString(s, 10)
+			if !ok {
+				return nil, errors.New("invalid big integer")
+			}
+			return n, nil
+		})
+		envstruct.RegisterFormatter(func(n *big.Int) string {
+			return n.String()
+		})
+
+		envstruct.RegisterParser(func(s string) (time.Month, error) {
+			for m := time.January; m <= time.December; m++ {
+				if strings.EqualFold(m.String(), s) {
+					return m, nil
+				}
+			}
+			return 0, fmt.Errorf("unknown month %q", s)
+		})
+
+		envstruct.RegisterParser(func(s string) (registryPoint, error) {
+			var p registryPoint
+			_, err := fmt.Sscanf(s, "%d:%d", &p.X, &p.Y)
+			return p, err
+		})
+		envstruct.RegisterFormatter(func(p registryPoint) string {
+			return fmt.Sprintf("%d:%d", p.X, p.Y)
+		})
+
+		for k, v := range registryEnvVars {
+			os.Setenv(k, v)
+		}
+
+		ts = RegistryTestStruct{}
+	})
+
+	AfterEach(func() {
+		for k := range registryEnvVars {
+			os.Unsetenv(k)
+		}
+
+		envstruct.ResetRegistry()
+	})
+
+	It("uses the registered parsers", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(ts.Big.String()).To(Equal("123456789012345678901234567890"))
+		Expect(ts.Month).To(Equal(time.October))
+		Expect(ts.Point).To(Equal(registryPoint{X: 1, Y: 2}))
+		Expect(ts.Points).To(Equal([]registryPoint{{X: 3, Y: 4}, {X: 5, Y: 6}}))
+	})
+
+	It("wraps errors of the registered parsers in a parse error", func() {
+		os.Setenv("REGISTRY_MONTH", "Smarch")
+
+		Expect(envstruct.Load(&ts)).To(MatchError(`failed to parse REGISTRY_MONTH as time.Month: unknown month "Smarch"`))
+	})
+
+	It("uses the registered formatters with ToEnv", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(envstruct.ToEnv(&ts)).To(ConsistOf(
+			"REGISTRY_BIG=123456789012345678901234567890",
+			"REGISTRY_MONTH=October",
+			"REGISTRY_POINT=1:2",
+			"REGISTRY_POINTS=3:4,5:6",
+		))
+	})
+
+	It("uses the registered formatters in the report", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		outputBuffer := bytes.NewBuffer(nil)
+		envstruct.ReportWriter = outputBuffer
+
+		Expect(envstruct.WriteReport(&ts)).To(Succeed())
+		Expect(outputBuffer.String()).To(MatchRegexp(`REGISTRY_POINT\s+false\s+1:2\n`))
+		Expect(outputBuffer.String()).To(ContainSubstring("[3:4 5:6]"))
+	})
+
+	It("does not call the registered formatters with unexported values", func() {
+		s := struct {
+			point registryPoint `env:"REGISTRY_POINT"`
+		}{registryPoint{X: 1, Y: 2}}
+
+		Expect(func() { envstruct.ToEnv(&s) }).ToNot(Panic())
+	})
+
+	It("removes the registered parsers when the registry is reset", func() {
+		envstruct.ResetRegistry()
+
+		Expect(envstruct.Load(&ts)).ToNot(Succeed())
+	})
+})
@@ -9,76 +9,305 @@ import (
 	"text/tabwriter"
 )
 
+const omittedValue = "(OMITTED)"
+
 // ReportWriter struct writing to stderr by default
 var ReportWriter io.Writer = os.Stderr
 
+// ReportRedactPatterns is a list of patterns, as understood by path.Match,
+// that are matched case insensitively against the environment variable and
+// field name of every field in a report. The values of matching fields are
+// omitted even if they have the `report` value in the `env` struct tag.
+// Patterns given with WithRedactPatterns are used in addition to these.
+var ReportRedactPatterns []string
+
+// ReportEntry describes a single field of a struct that is setup for
+// envstruct.
+type ReportEntry struct {
+	// Field is the path to the field from the root struct, e.g.
+	// `Config.Database.Password`.
+	Field string `json:"field"`
+
+	// Type is the Go type of the field.
+	Type string `json:"type"`
+
+	// EnvVar is the environment variable the field is read from.
+	EnvVar string `json:"env"`
+
+	// Required is true if the field has the `required` value in the `env`
+	// struct tag.
+	Required bool `json:"required"`
+
+	// Value is the value of the field, or `(OMITTED)` if the field does not
+	// have the `report` value in the `env` struct tag.
+	Value string `json:"value"`
+
+	// Source is SourceEnv if the environment variable is set and
+	// SourceDefault otherwise.
+	Source string `json:"source"`
+}
+
+// Sources of a ReportEntry value.
+const (
+	SourceEnv     = "env"
+	SourceDefault = "default"
+)
+
+// Report will take a struct that is setup for envstruct and return a
+// ReportEntry for every field with an `env` struct tag. As with WriteReport,
+// values are omitted or redacted according to the `env` struct tag and
+// WithRedactPatterns. If t has an EnvstructReport method generated by
+// envstructgen for its current struct tags, the fields it lists are reported
+// instead of walking t.
+func Report(t interface{}, opts ...ReportOption) ([]ReportEntry, error) {
+	var entries []ReportEntry
+
+	err := walkEnvFields(t, newReportOptions(opts), func(f envField) error {
+		entries = append(entries, f.reportEntry())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (f envField) reportEntry() ReportEntry {
+	value, _ := f.reportValue()
+
+	source := SourceDefault
+	if os.Getenv(f.envVar) != "" {
+		source = SourceEnv
+	}
+
+	return ReportEntry{
+		Field:    f.path,
+		Type:     f.value.Type().String(),
+		EnvVar:   f.envVar,
+		Required: f.required(),
+		Value:    value,
+		Source:   source,
+	}
+}
+
 // WriteReport will take a struct that is setup for envstruct and print
 // out a report containing the struct field name, field type, environment
 // variable for that field, whether or not the field is required and
 // the value of that field. The report is written to `ReportWriter`
 // which defaults to `os.Stderr`. By default all values are omitted. This
 // prevents logging of secrets. To not omit a value, you must add the `report`
-// value in the `env` struct tag.
-func WriteReport(t interface{}) error {
-	w := tabwriter.NewWriter(ReportWriter, 0, 8, 2, ' ', 0)
-
-	fmt.Fprintln(w, "FIELD NAME:\tTYPE:\tENV:\tREQUIRED:\tVALUE:")
+// value in the `env` struct tag. Values can instead be partially displayed
+// with `mask=lastN` (only the last N characters), `hash` (a short SHA-256
+// fingerprint) or, for URLs, `redact-userinfo` (the password is replaced).
+// Fields matching ReportRedactPatterns or the patterns given with
+// WithRedactPatterns are omitted even if tagged `report`.
+//
+// The report is written as a table unless a different format is selected
+// with WithFormat. An InvalidTargetError is returned if t is not a non-nil
+// pointer to a struct.
+func WriteReport(t interface{}, opts ...ReportOption) error {
+	o := newReportOptions(opts)
 
-	if err := writeReport(t, w); err != nil {
+	if _, err := targetStruct(t); err != nil {
 		return err
 	}
 
-	return w.Flush()
-}
+	if o.format == FormatTable {
+		w := tabwriter.NewWriter(ReportWriter, 0, 8, 2, ' ', 0)
 
-func writeReport(t interface{}, w io.Writer) error {
-	name := reflect.TypeOf(t).Elem().Name()
-	val := reflect.ValueOf(t).Elem()
+		fmt.Fprintln(w, "FIELD NAME:\tTYPE:\tENV:\tREQUIRED:\tVALUE:")
 
-	for i := 0; i < val.NumField(); i++ {
-		valueField := val.Field(i)
-		typeField := val.Type().Field(i)
-		tag := typeField.Tag
+		if err := writeReport(t, w, o); err != nil {
+			return err
+		}
 
-		// If field does not have the `env` tag, check to see if it is a struct,
-		// if it is not, then continue to next field, otherwise write the report
-		// for the sub struct.
-		if tag.Get("env") == "" {
-			if valueField.Kind() == reflect.Struct {
-				if err := writeReport(valueField.Addr().Interface(), w); err != nil {
-					return err
+		return w.Flush()
+	}
 
-				}
-			}
+	entries, err := Report(t, opts...)
+	if err != nil {
+		return err
+	}
 
-			if valueField.Kind() == reflect.Pointer {
-				if err := writeReport(valueField.Interface(), w); err != nil {
-					return err
+	return encodeReport(ReportWriter, entries, o.format)
+}
 
-				}
-			}
+// ReportOption configures WriteReport and the other functions that display
+// the values of a struct: Report, LogReport, LogValue, WriteUsage and
+// JSONSchema.
+type ReportOption func(*reportOptions)
 
-			continue
-		}
+type reportOptions struct {
+	format         ReportFormat
+	redactPatterns []string
+}
 
-		tagProperties := separateOnComma(tag.Get("env"))
-		envVar := strings.ToUpper(tagProperties[indexEnvVar])
-		isRequired := tagPropertiesContains(tagProperties, tagRequired)
+func newReportOptions(opts []ReportOption) reportOptions {
+	o := reportOptions{
+		format:         FormatTable,
+		redactPatterns: append([]string(nil), ReportRedactPatterns...),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
 
-		displayedValue := "(OMITTED)"
-		if tagPropertiesContains(tagProperties, tagReport) {
-			displayedValue = fmt.Sprint(valueField)
-		}
+	return o
+}
+
+// WithFormat selects the format WriteReport writes the report in. It is
+// ignored by the other functions.
+func WithFormat(f ReportFormat) ReportOption {
+	return func(o *reportOptions) {
+		o.format = f
+	}
+}
+
+// WithRedactPatterns adds patterns, as understood by path.Match, to those in
+// ReportRedactPatterns. The patterns are matched case insensitively against
+// the environment variable and field name of every field. The values of
+// matching fields are omitted even if they have the `report` value in the
+// `env` struct tag. Fields using `mask`, `hash` or `redact-userinfo` are still
+// displayed in their redacted form.
+func WithRedactPatterns(patterns ...string) ReportOption {
+	return func(o *reportOptions) {
+		o.redactPatterns = append(o.redactPatterns, patterns...)
+	}
+}
+
+func writeReport(t interface{}, w io.Writer, o reportOptions) error {
+	return walkEnvFields(t, o, func(f envField) error {
+		displayedValue, _ := f.reportValue()
 
 		fmt.Fprintf(w,
 			"%s.%v\t%v\t%v\t%t\t%v\n",
-			name,
-			typeField.Name,
-			valueField.Type(),
-			envVar,
-			isRequired,
+			f.structName,
+			f.field.Name,
+			f.value.Type(),
+			strings.ToUpper(f.envVar),
+			f.required(),
 			displayedValue)
+
+		return nil
+	})
+}
+
+// envField is a struct field carrying an `env` tag, as visited by
+// walkEnvFields.
+type envField struct {
+	structName    string
+	path          string
+	field         reflect.StructField
+	value         reflect.Value
+	envVar        string
+	tagProperties []string
+
+	// redactPatterns are ReportRedactPatterns and the patterns given with
+	// WithRedactPatterns.
+	redactPatterns []string
+}
+
+func (f envField) required() bool {
+	return tagPropertiesContains(f.tagProperties, tagRequired)
+}
+
+func (f envField) reported() bool {
+	return tagPropertiesContains(f.tagProperties, tagReport)
+}
+
+// walkEnvFields calls fn for every field of t that has an `env` tag. Fields
+// without the tag are descended into when they are structs, pointers to
+// structs or interfaces holding one, so that nested and embedded
+// configuration is visited as well. The fields of nil pointers to structs are
+// visited with their zero values. If t has an up to date EnvstructReport
+// method generated by envstructgen, the fields it lists are visited instead
+// of walking t.
+func walkEnvFields(t interface{}, o reportOptions, fn func(envField) error) error {
+	val, err := targetStruct(t)
+	if err != nil {
+		return err
+	}
+
+	withOptions := func(f envField) error {
+		f.redactPatterns = o.redactPatterns
+		return fn(f)
+	}
+
+	if r, ok := t.(envReporter); ok && checkGenerated(r, val) == nil {
+		return walkReportFields(val.Type(), r.EnvstructReport(), withOptions)
+	}
+
+	return walkEnvFieldsWithPath(val, val.Type().Name(), "", map[reflect.Type]bool{}, withOptions)
+}
+
+func walkEnvFieldsWithPath(
+	val reflect.Value,
+	path string,
+	prefix string,
+	visiting map[reflect.Type]bool,
+	fn func(envField) error,
+) error {
+	name := val.Type().Name()
+	visiting[val.Type()] = true
+	defer delete(visiting, val.Type())
+
+	plan := planFor(val.Type(), prefix)
+	for i := range plan.fields {
+		f := &plan.fields[i]
+
+		err := walkField(val.Field(i), f, name, path+"."+f.field.Name, visiting, fn)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
+
+// walkField calls fn for a single field of a struct named structName if it
+// has an `env` tag, or for the fields of the struct it refers to otherwise.
+func walkField(
+	valueField reflect.Value,
+	f *fieldPlan,
+	structName string,
+	path string,
+	visiting map[reflect.Type]bool,
+	fn func(envField) error,
+) error {
+	if !f.hasEnvTag {
+		nested, ok := nestedStruct(valueField, false)
+		if !ok {
+			nested, ok = zeroNestedStruct(valueField)
+			ok = ok && !visiting[nested.Type()]
+		}
+
+		if !ok {
+			return nil
+		}
+
+		return walkEnvFieldsWithPath(nested, path, f.nestedPrefix, visiting, fn)
+	}
+
+	return fn(envField{
+		structName:    structName,
+		path:          path,
+		field:         f.field,
+		value:         valueField,
+		envVar:        f.envVar(),
+		tagProperties: f.tagProperties,
+	})
+}
+
+// zeroNestedStruct returns a zero value of the struct a nil pointer field
+// refers to, so that its fields can be reported without allocating it.
+func zeroNestedStruct(value reflect.Value) (reflect.Value, bool) {
+	if value.Kind() != reflect.Pointer ||
+		!value.IsNil() ||
+		value.Type().Elem().Kind() != reflect.Struct ||
+		isLeafType(value.Type()) {
+
+		return reflect.Value{}, false
+	}
+
+	return reflect.New(value.Type().Elem()).Elem(), true
+}
@@ -0,0 +1,117 @@
+package envstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReportFormat is a format WriteReport can write the report in.
+type ReportFormat int
+
+const (
+	// FormatTable writes the report as an aligned table. This is the
+	// default.
+	FormatTable ReportFormat = iota
+
+	// FormatJSON writes the report as a JSON array of ReportEntry.
+	FormatJSON
+
+	// FormatYAML writes the report as a YAML list of key/value maps.
+	FormatYAML
+
+	// FormatMarkdown writes the report as a Markdown table.
+	FormatMarkdown
+
+	// FormatLogfmt writes the report as one logfmt line per field.
+	FormatLogfmt
+)
+
+func encodeReport(w io.Writer, entries []ReportEntry, format ReportFormat) error {
+	switch format {
+	case FormatJSON:
+		return encodeReportJSON(w, entries)
+	case FormatYAML:
+		return encodeReportYAML(w, entries)
+	case FormatMarkdown:
+		return encodeReportMarkdown(w, entries)
+	case FormatLogfmt:
+		return encodeReportLogfmt(w, entries)
+	}
+
+	return fmt.Errorf("unsupported report format %d", format)
+}
+
+func encodeReportJSON(w io.Writer, entries []ReportEntry) error {
+	if entries == nil {
+		entries = []ReportEntry{}
+	}
+
+	return json.NewEncoder(w).Encode(entries)
+}
+
+func encodeReportYAML(w io.Writer, entries []ReportEntry) error {
+	var b strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&b, "- field: %s\n", e.Field)
+		fmt.Fprintf(&b, "  type: %s\n", strconv.Quote(e.Type))
+		fmt.Fprintf(&b, "  env: %s\n", strconv.Quote(e.EnvVar))
+		fmt.Fprintf(&b, "  required: %t\n", e.Required)
+		fmt.Fprintf(&b, "  value: %s\n", strconv.Quote(e.Value))
+		fmt.Fprintf(&b, "  source: %s\n", e.Source)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func encodeReportMarkdown(w io.Writer, entries []ReportEntry) error {
+	var b strings.Builder
+	b.WriteString("| Field | Type | Env | Required | Value | Source |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "| %s | `%s` | `%s` | %t | %s | %s |\n",
+			e.Field,
+			e.Type,
+			e.EnvVar,
+			e.Required,
+			escapeMarkdownCell(e.Value),
+			e.Source,
+		)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func encodeReportLogfmt(w io.Writer, entries []ReportEntry) error {
+	var b strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&b, "field=%s type=%s env=%s required=%t value=%s source=%s\n",
+			logfmtValue(e.Field),
+			logfmtValue(e.Type),
+			logfmtValue(e.EnvVar),
+			e.Required,
+			logfmtValue(e.Value),
+			logfmtValue(e.Source),
+		)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func logfmtValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"\t\n\\") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
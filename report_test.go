@@ -38,8 +38,85 @@ var _ = Describe("Report", func() {
 			Expect(outputText).To(Equal(expectedReportOutput))
 		})
 	})
+
+	Describe("Report()", func() {
+		var rs ReportTestStruct
+
+		BeforeEach(func() {
+			os.Setenv("REPORT_HOST", "example.com")
+			os.Setenv("REPORT_PASSWORD", "secret")
+
+			rs = ReportTestStruct{Port: 8080}
+			Expect(envstruct.Load(&rs)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("REPORT_HOST")
+			os.Unsetenv("REPORT_PASSWORD")
+		})
+
+		It("returns an entry for every field", func() {
+			entries, err := envstruct.Report(&rs)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(entries).To(Equal([]envstruct.ReportEntry{
+				{
+					Field:    "ReportTestStruct.Host",
+					Type:     "string",
+					EnvVar:   "REPORT_HOST",
+					Required: true,
+					Value:    "example.com",
+					Source:   envstruct.SourceEnv,
+				},
+				{
+					Field:  "ReportTestStruct.Port",
+					Type:   "int",
+					EnvVar: "REPORT_PORT",
+					Value:  "8080",
+					Source: envstruct.SourceDefault,
+				},
+				{
+					Field:  "ReportTestStruct.Sub.Password",
+					Type:   "string",
+					EnvVar: "REPORT_PASSWORD",
+					Value:  "(OMITTED)",
+					Source: envstruct.SourceEnv,
+				},
+			}))
+		})
+
+		DescribeTable("writes the report in the given format",
+			func(format envstruct.ReportFormat, expected string) {
+				outputBuffer := bytes.NewBuffer(nil)
+				envstruct.ReportWriter = outputBuffer
+
+				Expect(envstruct.WriteReport(&rs, envstruct.WithFormat(format))).To(Succeed())
+				Expect(outputBuffer.String()).To(Equal(expected))
+			},
+			Entry("JSON", envstruct.FormatJSON, expectedJSONReportOutput),
+			Entry("YAML", envstruct.FormatYAML, expectedYAMLReportOutput),
+			Entry("Markdown", envstruct.FormatMarkdown, expectedMarkdownReportOutput),
+			Entry("logfmt", envstruct.FormatLogfmt, expectedLogfmtReportOutput),
+		)
+
+		It("returns an error for an unknown format", func() {
+			envstruct.ReportWriter = bytes.NewBuffer(nil)
+
+			Expect(envstruct.WriteReport(&rs, envstruct.WithFormat(99))).To(MatchError("unsupported report format 99"))
+		})
+	})
 })
 
+type ReportTestStruct struct {
+	Host string `env:"REPORT_HOST,required,report"`
+	Port int    `env:"REPORT_PORT,report"`
+	Sub  ReportTestSubStruct
+}
+
+type ReportTestSubStruct struct {
+	Password string `env:"REPORT_PASSWORD"`
+}
+
 const (
 	expectedReportOutput = `FIELD NAME:                         TYPE:       ENV:                  REQUIRED:  VALUE:
 SmallTestStruct.HiddenThing         string      HIDDEN_THING          false      (OMITTED)
@@ -53,5 +130,40 @@ SmallTestStruct.StringSliceThing    []string    STRING_SLICE_THING    false
 SmallTestStruct.CaseSensitiveThing  string      CASE_SENSITIVE_THING  false      case sensitive
 SmallTestSubStruct.SecretThing      string      SECRET_THING          false      (OMITTED)
 SmallTestSubStruct.SecretThing      string      SECRET_THING          false      (OMITTED)
+`
+
+	expectedJSONReportOutput = `[{"field":"ReportTestStruct.Host","type":"string","env":"REPORT_HOST","required":true,"value":"example.com","source":"env"},{"field":"ReportTestStruct.Port","type":"int","env":"REPORT_PORT","required":false,"value":"8080","source":"default"},{"field":"ReportTestStruct.Sub.Password","type":"string","env":"REPORT_PASSWORD","required":false,"value":"(OMITTED)","source":"env"}]
+`
+
+	expectedYAMLReportOutput = `- field: ReportTestStruct.Host
+  type: "string"
+  env: "REPORT_HOST"
+  required: true
+  value: "example.com"
+  source: env
+- field: ReportTestStruct.Port
+  type: "int"
+  env: "REPORT_PORT"
+  required: false
+  value: "8080"
+  source: default
+- field: ReportTestStruct.Sub.Password
+  type: "string"
+  env: "REPORT_PASSWORD"
+  required: false
+  value: "(OMITTED)"
+  source: env
+`
+
+	expectedMarkdownReportOutput = `| Field | Type | Env | Required | Value | Source |
+| --- | --- | --- | --- | --- | --- |
+| ReportTestStruct.Host | ` + "`string`" + ` | ` + "`REPORT_HOST`" + ` | true | example.com | env |
+| ReportTestStruct.Port | ` + "`int`" + ` | ` + "`REPORT_PORT`" + ` | false | 8080 | default |
+| ReportTestStruct.Sub.Password | ` + "`string`" + ` | ` + "`REPORT_PASSWORD`" + ` | false | (OMITTED) | env |
+`
+
+	expectedLogfmtReportOutput = `field=ReportTestStruct.Host type=string env=REPORT_HOST required=true value=example.com source=env
+field=ReportTestStruct.Port type=int env=REPORT_PORT required=false value=8080 source=default
+field=ReportTestStruct.Sub.Password type=string env=REPORT_PASSWORD required=false value=(OMITTED) source=env
 `
 )
@@ -0,0 +1,126 @@
+package envstruct
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema will take a struct that is setup for envstruct and return a
+// JSON Schema describing the environment it reads: an object with a property
+// for every environment variable. As environment variables are strings, every
+// property is of type string. Properties carry the `description` struct tag,
+// the values allowed by `enum=a|b` in the `env` struct tag, as a pattern
+// for comma separated lists if the field is a slice or an array, and the Go
+// type of the field as `x-go-type`. Fields tagged `required` are listed as
+// required.
+//
+// The current value of a field that is tagged `report` is used as its
+// default, unless it is the zero value. Secret fields, fields matching the
+// patterns given with WithRedactPatterns and fields that are masked or hashed
+// in reports are marked `writeOnly` and never have a default.
+func JSONSchema(t interface{}, opts ...ReportOption) ([]byte, error) {
+	schema := jsonSchema{
+		Schema:     jsonSchemaDraft,
+		Type:       "object",
+		Properties: map[string]jsonSchemaProperty{},
+	}
+
+	if val, err := targetStruct(t); err == nil {
+		schema.Title = val.Type().Name()
+	}
+
+	err := walkEnvFields(t, newReportOptions(opts), func(f envField) error {
+		property := jsonSchemaProperty{
+			Type:        "string",
+			Description: f.field.Tag.Get(tagDescription),
+			WriteOnly:   f.secret(),
+			GoType:      f.value.Type().String(),
+		}
+
+		if values, ok := enumValues(f.tagProperties); ok {
+			if isList(f.value.Type()) {
+				property.Pattern = enumListPattern(values)
+			} else {
+				property.Enum = values
+			}
+		}
+
+		if f.reportsFullValue() && !isZeroValue(f.value) {
+			property.Default = envValue(f)
+		}
+
+		if f.required() {
+			schema.Required = append(schema.Required, f.envVar)
+		}
+
+		schema.Properties[f.envVar] = property
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	schema.Required = uniqueStrings(schema.Required)
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+type jsonSchema struct {
+	Schema     string                        `json:"$schema"`
+	Title      string                        `json:"title,omitempty"`
+	Type       string                        `json:"type"`
+	Properties map[string]jsonSchemaProperty `json:"properties"`
+	Required   []string                      `json:"required,omitempty"`
+}
+
+type jsonSchemaProperty struct {
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Pattern     string   `json:"pattern,omitempty"`
+	WriteOnly   bool     `json:"writeOnly,omitempty"`
+	GoType      string   `json:"x-go-type"`
+}
+
+// enumListPattern returns a pattern matching comma separated lists of values.
+func enumListPattern(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = regexp.QuoteMeta(v)
+	}
+
+	value := "(" + strings.Join(quoted, "|") + ")"
+	return "^" + value + "( *, *" + value + ")*$"
+}
+
+// secret returns true if the value of the field must not be displayed,
+// because it is a Secret, matches the redact patterns or is only displayed
+// masked or hashed.
+func (f envField) secret() bool {
+	if f.value.CanInterface() {
+		if _, ok := f.value.Interface().(revealer); ok {
+			return true
+		}
+	}
+
+	return matchesRedactPatterns(f) ||
+		tagPropertiesContains(f.tagProperties, tagHash) ||
+		tagPropertiesHasKey(f.tagProperties, tagMask)
+}
+
+// envValue returns the value of the field as it would be set in the
+// environment, as formatted by ToEnv.
+func envValue(f envField) string {
+	for _, kv := range formatEnv(f.value, f.tagProperties, "") {
+		if v, ok := strings.CutPrefix(kv, f.envVar+"="); ok {
+			return v
+		}
+	}
+
+	return ""
+}
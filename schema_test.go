@@ -0,0 +1,146 @@
+package envstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type SchemaDatabase struct {
+	Host     string                   `env:"HOST,required,report" description:"database host"`
+	Password envstruct.Secret[string] `env:"PASSWORD,required"`
+}
+
+type SchemaTestStruct struct {
+	LogLevel string             `env:"LOG_LEVEL,enum=debug|info|warn,report" description:"log level"`
+	Port     int                `env:"PORT,report"`
+	MaxBody  envstruct.ByteSize `env:"MAX_BODY,report"`
+	Token    string             `env:"TOKEN,mask=last4"`
+	DB       SchemaDatabase     `envprefix:"DB_"`
+}
+
+var _ = Describe("JSONSchema", func() {
+	It("describes the environment variables of a struct", func() {
+		ts := SchemaTestStruct{
+			LogLevel: "info",
+			MaxBody:  10 * envstruct.MiB,
+			Token:    "abcdef",
+		}
+
+		schema, err := envstruct.JSONSchema(&ts)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(schema).To(MatchJSON(`{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"title": "SchemaTestStruct",
+			"type": "object",
+			"properties": {
+				"LOG_LEVEL": {
+					"type": "string",
+					"description": "log level",
+					"default": "info",
+					"enum": ["debug", "info", "warn"],
+					"x-go-type": "string"
+				},
+				"PORT": {
+					"type": "string",
+					"x-go-type": "int"
+				},
+				"MAX_BODY": {
+					"type": "string",
+					"default": "10MiB",
+					"x-go-type": "envstruct.ByteSize"
+				},
+				"TOKEN": {
+					"type": "string",
+					"writeOnly": true,
+					"x-go-type": "string"
+				},
+				"DB_HOST": {
+					"type": "string",
+					"description": "database host",
+					"x-go-type": "string"
+				},
+				"DB_PASSWORD": {
+					"type": "string",
+					"writeOnly": true,
+					"x-go-type": "envstruct.Secret[string]"
+				}
+			},
+			"required": ["DB_HOST", "DB_PASSWORD"]
+		}`))
+	})
+
+	It("returns an error for invalid targets", func() {
+		_, err := envstruct.JSONSchema(SchemaTestStruct{})
+
+		var targetErr *envstruct.InvalidTargetError
+		Expect(errors.As(err, &targetErr)).To(BeTrue())
+	})
+
+	Describe("enum", func() {
+		AfterEach(func() {
+			os.Unsetenv("LOG_LEVEL")
+		})
+
+		It("loads allowed values", func() {
+			os.Setenv("LOG_LEVEL", "warn")
+
+			var ts SchemaTestStruct
+			Expect(envstruct.Load(&ts)).To(MatchError(ContainSubstring("missing required")))
+			Expect(ts.LogLevel).To(Equal("warn"))
+		})
+
+		It("returns a parse error for other values", func() {
+			os.Setenv("LOG_LEVEL", "trace")
+
+			err := envstruct.Load(&SchemaTestStruct{})
+			Expect(err).To(MatchError("failed to parse LOG_LEVEL as string: must be one of debug, info, warn"))
+		})
+
+		Context("on slices", func() {
+			type levels struct {
+				Levels []string `env:"LOG_LEVELS,enum=debug|info"`
+			}
+
+			AfterEach(func() {
+				os.Unsetenv("LOG_LEVELS")
+			})
+
+			It("checks every element", func() {
+				os.Setenv("LOG_LEVELS", "debug,info")
+
+				var ts levels
+				Expect(envstruct.Load(&ts)).To(Succeed())
+				Expect(ts.Levels).To(Equal([]string{"debug", "info"}))
+
+				os.Setenv("LOG_LEVELS", "debug,trace")
+				Expect(envstruct.Load(&ts)).To(MatchError("failed to parse LOG_LEVELS as []string: must be one of debug, info"))
+			})
+
+			It("describes the allowed lists with a pattern", func() {
+				schema, err := envstruct.JSONSchema(&levels{})
+				Expect(err).ToNot(HaveOccurred())
+
+				var s struct {
+					Properties map[string]struct {
+						Enum    []string `json:"enum"`
+						Pattern string   `json:"pattern"`
+					} `json:"properties"`
+				}
+				Expect(json.Unmarshal(schema, &s)).To(Succeed())
+
+				property := s.Properties["LOG_LEVELS"]
+				Expect(property.Enum).To(BeEmpty())
+				Expect(property.Pattern).To(Equal("^(debug|info)( *, *(debug|info))*$"))
+				Expect("debug, info").To(MatchRegexp(property.Pattern))
+				Expect("debug,trace").ToNot(MatchRegexp(property.Pattern))
+			})
+		})
+	})
+})
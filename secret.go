@@ -0,0 +1,97 @@
+package envstruct
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+)
+
+// Secret holds a value that must never be printed. Load populates a Secret
+// from its environment variable as it would a field of type T, including the
+// options of the `env` tag such as `encoding` and `layout`, but the value is
+// redacted when formatted with fmt, marshalled to JSON or logged with slog
+// and parse errors do not include it. The value is only available through
+// Value.
+type Secret[T any] struct {
+	value T
+}
+
+// NewSecret returns a Secret holding v.
+func NewSecret[T any](v T) Secret[T] {
+	return Secret[T]{value: v}
+}
+
+// Value returns the secret value.
+func (s Secret[T]) Value() T {
+	return s.value
+}
+
+// UnmarshalEnv implements Unmarshaller.
+func (s *Secret[T]) UnmarshalEnv(v string) error {
+	return s.unmarshalEnvWithTags(v, nil)
+}
+
+// unmarshalEnvWithTags sets the value from v as Load sets a field of type T
+// with the given `env` tag properties. Parse errors do not include v.
+func (s *Secret[T]) unmarshalEnvWithTags(v string, tagProperties []string) error {
+	value := reflect.ValueOf(&s.value).Elem()
+
+	_, err := setField(value, v, tagProperties)
+	if err == nil {
+		return nil
+	}
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		parseErr = &ParseError{Type: value.Type(), Err: err}
+	}
+	parseErr.redacted = true
+
+	return parseErr
+}
+
+// String implements fmt.Stringer.
+func (s Secret[T]) String() string {
+	return omittedValue
+}
+
+// GoString implements fmt.GoStringer.
+func (s Secret[T]) GoString() string {
+	return omittedValue
+}
+
+// Format implements fmt.Formatter so that the value is redacted for every
+// verb and flag.
+func (s Secret[T]) Format(f fmt.State, _ rune) {
+	fmt.Fprint(f, omittedValue)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(omittedValue)
+}
+
+// LogValue implements slog.LogValuer.
+func (s Secret[T]) LogValue() slog.Value {
+	return slog.StringValue(omittedValue)
+}
+
+func (s Secret[T]) reveal() reflect.Value {
+	return reflect.ValueOf(&s.value).Elem()
+}
+
+// tagUnmarshaller is implemented by Secret so that the `env` tag options of
+// the field apply to the secret value.
+type tagUnmarshaller interface {
+	unmarshalEnvWithTags(v string, tagProperties []string) error
+}
+
+// revealer is implemented by Secret so that ToEnv can write out the secret
+// value.
+type revealer interface {
+	reveal() reflect.Value
+}
+
+var revealerType = reflect.TypeOf((*revealer)(nil)).Elem()
@@ -0,0 +1,177 @@
+package envstruct_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type SecretTestStruct struct {
+	Password envstruct.Secret[string]   `env:"SECRET_PASSWORD,report"`
+	PIN      envstruct.Secret[int]      `env:"SECRET_PIN"`
+	Keys     envstruct.Secret[[]string] `env:"SECRET_KEYS"`
+}
+
+var _ = Describe("Secret", func() {
+	var ts SecretTestStruct
+
+	BeforeEach(func() {
+		os.Setenv("SECRET_PASSWORD", "hunter2")
+		os.Setenv("SECRET_PIN", "1234")
+		os.Setenv("SECRET_KEYS", "key-a,key-b")
+
+		ts = SecretTestStruct{}
+		Expect(envstruct.Load(&ts)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("SECRET_PASSWORD")
+		os.Unsetenv("SECRET_PIN")
+		os.Unsetenv("SECRET_KEYS")
+	})
+
+	It("is populated by Load", func() {
+		Expect(ts.Password.Value()).To(Equal("hunter2"))
+		Expect(ts.PIN.Value()).To(Equal(1234))
+		Expect(ts.Keys.Value()).To(Equal([]string{"key-a", "key-b"}))
+	})
+
+	It("keeps the default value when the env var is empty", func() {
+		os.Setenv("SECRET_PIN", "")
+		ts = SecretTestStruct{PIN: envstruct.NewSecret(42)}
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+		Expect(ts.PIN.Value()).To(Equal(42))
+	})
+
+	It("returns an error for an invalid value", func() {
+		os.Setenv("SECRET_PIN", "not-a-number")
+
+		Expect(envstruct.Load(&ts)).ToNot(Succeed())
+	})
+
+	It("is redacted when formatted", func() {
+		for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%d", "%x"} {
+			out := fmt.Sprintf(format, ts)
+			Expect(out).ToNot(ContainSubstring("hunter2"), format)
+			Expect(out).ToNot(ContainSubstring("1234"), format)
+			Expect(out).ToNot(ContainSubstring("key-a"), format)
+		}
+
+		Expect(ts.Password.String()).To(Equal("(OMITTED)"))
+		Expect(ts.Password.GoString()).To(Equal("(OMITTED)"))
+	})
+
+	It("is redacted when marshalled to JSON", func() {
+		out, err := json.Marshal(ts)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(out).To(MatchJSON(`{
+			"Password": "(OMITTED)",
+			"PIN": "(OMITTED)",
+			"Keys": "(OMITTED)"
+		}`))
+	})
+
+	It("is redacted when logged with slog", func() {
+		outputBuffer := bytes.NewBuffer(nil)
+		logger := slog.New(slog.NewTextHandler(outputBuffer, nil))
+
+		logger.Info("loaded", "password", ts.Password)
+
+		Expect(outputBuffer.String()).To(ContainSubstring("password=(OMITTED)"))
+		Expect(outputBuffer.String()).ToNot(ContainSubstring("hunter2"))
+	})
+
+	It("is redacted in the report even when tagged with report", func() {
+		outputBuffer := bytes.NewBuffer(nil)
+		envstruct.ReportWriter = outputBuffer
+
+		Expect(envstruct.WriteReport(&ts)).To(Succeed())
+		Expect(outputBuffer.String()).ToNot(ContainSubstring("hunter2"))
+	})
+
+	It("is included by ToEnv", func() {
+		Expect(envstruct.ToEnv(&ts)).To(ConsistOf(
+			"SECRET_PASSWORD=hunter2",
+			"SECRET_PIN=1234",
+			"SECRET_KEYS=key-a,key-b",
+		))
+	})
+
+	Context("with env tag options", func() {
+		var tagged struct {
+			Key     envstruct.Secret[[]byte]        `env:"SECRET_KEY,encoding=hex"`
+			Expiry  envstruct.Secret[time.Time]     `env:"SECRET_EXPIRY,layout=2006-01-02"`
+			Timeout envstruct.Secret[time.Duration] `env:"SECRET_TIMEOUT,extended"`
+		}
+
+		BeforeEach(func() {
+			os.Setenv("SECRET_KEY", "deadbeef")
+			os.Setenv("SECRET_EXPIRY", "2030-01-02")
+			os.Setenv("SECRET_TIMEOUT", "1d")
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("SECRET_KEY")
+			os.Unsetenv("SECRET_EXPIRY")
+			os.Unsetenv("SECRET_TIMEOUT")
+		})
+
+		It("applies them to the value", func() {
+			Expect(envstruct.Load(&tagged)).To(Succeed())
+
+			Expect(tagged.Key.Value()).To(Equal([]byte{0xde, 0xad, 0xbe, 0xef}))
+			Expect(tagged.Expiry.Value()).To(Equal(time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)))
+			Expect(tagged.Timeout.Value()).To(Equal(24 * time.Hour))
+		})
+
+		It("round trips through ToEnv", func() {
+			Expect(envstruct.Load(&tagged)).To(Succeed())
+
+			Expect(envstruct.ToEnv(&tagged)).To(ContainElements(
+				"SECRET_KEY=deadbeef",
+				"SECRET_EXPIRY=2030-01-02",
+			))
+		})
+
+		It("returns an error for a value not matching them", func() {
+			os.Setenv("SECRET_KEY", "c2VjcmV0LWtleQ==")
+
+			Expect(envstruct.Load(&tagged)).ToNot(Succeed())
+		})
+	})
+
+	It("does not include the value in parse errors", func() {
+		for _, env := range []struct{ name, value string }{
+			{"SECRET_PIN", "hunter2-pin"},
+			{"SECRET_EXPIRY", "hunter2-expiry"},
+			{"SECRET_KEY", "hunter2-key"},
+		} {
+			os.Setenv(env.name, env.value)
+
+			var tagged struct {
+				PIN    envstruct.Secret[int]       `env:"SECRET_PIN"`
+				Expiry envstruct.Secret[time.Time] `env:"SECRET_EXPIRY,layout=2006-01-02"`
+				Key    envstruct.Secret[[]byte]    `env:"SECRET_KEY,encoding=hex"`
+			}
+			err := envstruct.Load(&tagged)
+			os.Unsetenv(env.name)
+
+			var parseErr *envstruct.ParseError
+			Expect(errors.As(err, &parseErr)).To(BeTrue(), env.name)
+			Expect(parseErr.EnvVar).To(Equal(env.name))
+			Expect(err.Error()).To(HavePrefix("failed to parse " + env.name))
+			Expect(err.Error()).ToNot(ContainSubstring("hunter2"))
+		}
+	})
+})
@@ -0,0 +1,90 @@
+package envstruct
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+)
+
+// LogReport will take a struct that is setup for envstruct and log the
+// report to logger at info level, as a group of attributes named `config`
+// keyed by environment variable. Values are omitted or redacted in the same
+// way as WriteReport.
+func LogReport(logger *slog.Logger, t interface{}, opts ...ReportOption) error {
+	attrs, err := reportAttrs(t, opts)
+	if err != nil {
+		return err
+	}
+
+	logger.LogAttrs(context.Background(), slog.LevelInfo, "configuration", slog.Attr{
+		Key:   "config",
+		Value: slog.GroupValue(attrs...),
+	})
+
+	return nil
+}
+
+// LogValue returns the report of a struct that is setup for envstruct as a
+// slog group value. It is meant to be used to implement slog.LogValuer:
+//
+//	func (c Config) LogValue() slog.Value {
+//		return envstruct.LogValue(&c)
+//	}
+func LogValue(t interface{}, opts ...ReportOption) slog.Value {
+	attrs, err := reportAttrs(t, opts)
+	if err != nil {
+		return slog.StringValue(fmt.Sprintf("!ERROR: %s", err))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+func reportAttrs(t interface{}, opts []ReportOption) ([]slog.Attr, error) {
+	var attrs []slog.Attr
+
+	err := walkEnvFields(t, newReportOptions(opts), func(f envField) error {
+		value := slogValue(f.value, f.tagProperties)
+		if !f.reportsFullValue() {
+			s, _ := f.reportValue()
+			value = slog.StringValue(s)
+		}
+
+		attrs = append(attrs, slog.Attr{Key: f.envVar, Value: value})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return attrs, nil
+}
+
+func slogValue(v reflect.Value, tagProperties []string) slog.Value {
+	if v.Type() == reflect.TypeOf(time.Second) {
+		return slog.DurationValue(time.Duration(v.Int()))
+	}
+
+	// Types with their own formatting, such as ByteSize, are logged as they
+	// are displayed in the report rather than according to their kind.
+	if _, ok := lookupFormatter(v.Type()); ok {
+		return slog.StringValue(displayString(v, tagProperties))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return slog.StringValue(v.String())
+	case reflect.Bool:
+		return slog.BoolValue(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return slog.Int64Value(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return slog.Uint64Value(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return slog.Float64Value(v.Float())
+	}
+
+	return slog.StringValue(displayString(v, tagProperties))
+}
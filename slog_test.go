@@ -0,0 +1,111 @@
+package envstruct_test
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"time"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type SlogTestStruct struct {
+	Host     string        `env:"SLOG_HOST,report"`
+	Port     int           `env:"SLOG_PORT,report"`
+	Timeout  time.Duration `env:"SLOG_TIMEOUT,report"`
+	Password string        `env:"SLOG_PASSWORD"`
+}
+
+func (s SlogTestStruct) LogValue() slog.Value {
+	return envstruct.LogValue(&s)
+}
+
+var _ = Describe("slog", func() {
+	var (
+		ts           SlogTestStruct
+		outputBuffer *bytes.Buffer
+		logger       *slog.Logger
+	)
+
+	BeforeEach(func() {
+		os.Setenv("SLOG_HOST", "example.com")
+		os.Setenv("SLOG_PORT", "8080")
+		os.Setenv("SLOG_TIMEOUT", "5s")
+		os.Setenv("SLOG_PASSWORD", "secret")
+
+		ts = SlogTestStruct{}
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		outputBuffer = bytes.NewBuffer(nil)
+		logger = slog.New(slog.NewJSONHandler(outputBuffer, &slog.HandlerOptions{
+			ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("SLOG_HOST")
+		os.Unsetenv("SLOG_PORT")
+		os.Unsetenv("SLOG_TIMEOUT")
+		os.Unsetenv("SLOG_PASSWORD")
+	})
+
+	Describe("LogReport()", func() {
+		It("logs the report as a group of attributes", func() {
+			Expect(envstruct.LogReport(logger, &ts)).To(Succeed())
+
+			Expect(outputBuffer.String()).To(MatchJSON(`{
+				"level": "INFO",
+				"msg": "configuration",
+				"config": {
+					"SLOG_HOST": "example.com",
+					"SLOG_PORT": 8080,
+					"SLOG_TIMEOUT": 5000000000,
+					"SLOG_PASSWORD": "(OMITTED)"
+				}
+			}`))
+		})
+	})
+
+	Describe("LogValue()", func() {
+		It("can be used to implement slog.LogValuer", func() {
+			logger.Info("loaded", "config", ts)
+
+			Expect(outputBuffer.String()).To(MatchJSON(`{
+				"level": "INFO",
+				"msg": "loaded",
+				"config": {
+					"SLOG_HOST": "example.com",
+					"SLOG_PORT": 8080,
+					"SLOG_TIMEOUT": 5000000000,
+					"SLOG_PASSWORD": "(OMITTED)"
+				}
+			}`))
+		})
+
+		It("logs types with their own formatting as they are reported", func() {
+			os.Setenv("SLOG_MAX_SIZE", "10MiB")
+			defer os.Unsetenv("SLOG_MAX_SIZE")
+
+			sized := struct {
+				MaxSize envstruct.ByteSize `env:"SLOG_MAX_SIZE,report"`
+			}{}
+			Expect(envstruct.Load(&sized)).To(Succeed())
+
+			entries, err := envstruct.Report(&sized)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(envstruct.LogValue(&sized).Group()).To(Equal([]slog.Attr{
+				slog.String("SLOG_MAX_SIZE", entries[0].Value),
+			}))
+			Expect(entries[0].Value).To(Equal("10 MiB"))
+		})
+	})
+})
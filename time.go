@@ -0,0 +1,155 @@
+package envstruct
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	// Embed the IANA time zone database so that *time.Location fields can
+	// be loaded on systems without one.
+	_ "time/tzdata"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+func setDuration(value reflect.Value, input string, tagProperties []string) error {
+	parse := time.ParseDuration
+	if tagPropertiesContains(tagProperties, tagExtended) {
+		parse = parseExtendedDuration
+	}
+
+	d, err := parse(input)
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.ValueOf(d))
+
+	return nil
+}
+
+// parseExtendedDuration parses a duration as time.ParseDuration does, but
+// also accepts the units `d` (24 hours) and `w` (7 days), e.g. `1w2d12h`.
+func parseExtendedDuration(input string) (time.Duration, error) {
+	s := input
+	sign := time.Duration(1)
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		if s[0] == '-' {
+			sign = -1
+		}
+		s = s[1:]
+	}
+
+	if s == "" {
+		return 0, fmt.Errorf("time: invalid duration %q", input)
+	}
+
+	var d time.Duration
+	for s != "" {
+		n := strings.IndexFunc(s, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.'
+		})
+		if n <= 0 {
+			return 0, fmt.Errorf("time: invalid duration %q", input)
+		}
+
+		u := strings.IndexFunc(s[n:], func(r rune) bool {
+			return (r >= '0' && r <= '9') || r == '.'
+		})
+		if u < 0 {
+			u = len(s) - n
+		}
+
+		number, unit := s[:n], s[n:n+u]
+		s = s[n+u:]
+
+		switch unit {
+		case "d", "w":
+			f, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return 0, fmt.Errorf("time: invalid duration %q", input)
+			}
+
+			size := day
+			if unit == "w" {
+				size = week
+			}
+
+			part := f * float64(size)
+			if part >= math.MaxInt64 {
+				return 0, fmt.Errorf("time: invalid duration %q", input)
+			}
+			d, err = addDuration(d, time.Duration(part), input)
+			if err != nil {
+				return 0, err
+			}
+		default:
+			part, err := time.ParseDuration(number + unit)
+			if err != nil {
+				return 0, fmt.Errorf("time: invalid duration %q", input)
+			}
+			d, err = addDuration(d, part, input)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return sign * d, nil
+}
+
+// addDuration returns d + part, or an error if the sum overflows.
+func addDuration(d, part time.Duration, input string) (time.Duration, error) {
+	if d > math.MaxInt64-part {
+		return 0, fmt.Errorf("time: invalid duration %q", input)
+	}
+
+	return d + part, nil
+}
+
+func setTime(value reflect.Value, input string, tagProperties []string) error {
+	t, err := time.Parse(timeLayout(tagProperties), input)
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.ValueOf(t))
+
+	return nil
+}
+
+func setLocation(value reflect.Value, input string) error {
+	loc, err := time.LoadLocation(input)
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.ValueOf(loc))
+
+	return nil
+}
+
+func formatTime(value reflect.Value, tagProperties []string) string {
+	if !value.CanInterface() {
+		return omittedValue
+	}
+
+	t := value.Interface().(time.Time)
+	return t.Format(timeLayout(tagProperties))
+}
+
+// timeLayout returns the layout given with the `layout` tag option, or
+// time.RFC3339 if there is none.
+func timeLayout(tagProperties []string) string {
+	if layout, ok := tagPropertyValue(tagProperties, tagLayout); ok {
+		return layout
+	}
+
+	return time.RFC3339
+}
@@ -0,0 +1,143 @@
+package envstruct_test
+
+import (
+	"bytes"
+	"os"
+	"time"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type TimeTestStruct struct {
+	Cutoff    time.Time      `env:"TIME_CUTOFF,report"`
+	Date      time.Time      `env:"TIME_DATE,layout=2006-01-02,report"`
+	Posted    time.Time      `env:"TIME_POSTED,layout='Mon, 02 Jan 2006',report"`
+	Location  *time.Location `env:"TIME_LOCATION,report"`
+	Retention time.Duration  `env:"TIME_RETENTION,extended,report"`
+	Timeout   time.Duration  `env:"TIME_TIMEOUT,report"`
+}
+
+var timeEnvVars = map[string]string{
+	"TIME_CUTOFF":    "2026-10-19T09:30:00Z",
+	"TIME_DATE":      "2026-10-19",
+	"TIME_POSTED":    "Mon, 19 Oct 2026",
+	"TIME_LOCATION":  "Europe/Berlin",
+	"TIME_RETENTION": "1w2d12h",
+	"TIME_TIMEOUT":   "30s",
+}
+
+var _ = Describe("time types", func() {
+	var ts TimeTestStruct
+
+	BeforeEach(func() {
+		for k, v := range timeEnvVars {
+			os.Setenv(k, v)
+		}
+
+		ts = TimeTestStruct{}
+	})
+
+	AfterEach(func() {
+		for k := range timeEnvVars {
+			os.Unsetenv(k)
+		}
+	})
+
+	It("parses the values", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(ts.Cutoff).To(Equal(time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)))
+		Expect(ts.Date).To(Equal(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)))
+		Expect(ts.Posted).To(Equal(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)))
+		Expect(ts.Location.String()).To(Equal("Europe/Berlin"))
+		Expect(ts.Retention).To(Equal(9*24*time.Hour + 12*time.Hour))
+		Expect(ts.Timeout).To(Equal(30 * time.Second))
+	})
+
+	It("keeps the default value when the env var is empty", func() {
+		os.Setenv("TIME_CUTOFF", "")
+		os.Setenv("TIME_LOCATION", "")
+		ts.Cutoff = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		ts.Location = time.UTC
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+		Expect(ts.Cutoff).To(Equal(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)))
+		Expect(ts.Location).To(Equal(time.UTC))
+	})
+
+	It("only accepts days and weeks with the extended option", func() {
+		os.Setenv("TIME_TIMEOUT", "7d")
+
+		Expect(envstruct.Load(&ts)).ToNot(Succeed())
+	})
+
+	DescribeTable("returns an error for invalid values",
+		func(envVar, value string) {
+			os.Setenv(envVar, value)
+
+			Expect(envstruct.Load(&ts)).ToNot(Succeed())
+		},
+		Entry("time.Time", "TIME_CUTOFF", "2026-10-19"),
+		Entry("time.Time with layout", "TIME_DATE", "19.10.2026"),
+		Entry("*time.Location", "TIME_LOCATION", "Mars/Olympus_Mons"),
+		Entry("extended duration", "TIME_RETENTION", "7x"),
+		Entry("extended duration without unit", "TIME_RETENTION", "7"),
+		Entry("extended duration that overflows", "TIME_RETENTION", "999999999999d"),
+		Entry("extended durations that overflow together", "TIME_RETENTION", "15000w15000w"),
+	)
+
+	It("formats the values with ToEnv", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(envstruct.ToEnv(&ts)).To(ConsistOf(
+			"TIME_CUTOFF=2026-10-19T09:30:00Z",
+			"TIME_DATE=2026-10-19",
+			"TIME_POSTED=Mon, 19 Oct 2026",
+			"TIME_LOCATION=Europe/Berlin",
+			"TIME_RETENTION=228h0m0s",
+			"TIME_TIMEOUT=30s",
+		))
+	})
+
+	It("displays the values in the report", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		outputBuffer := bytes.NewBuffer(nil)
+		envstruct.ReportWriter = outputBuffer
+
+		Expect(envstruct.WriteReport(&ts)).To(Succeed())
+		Expect(outputBuffer.String()).To(ContainSubstring("2026-10-19 09:30:00 +0000 UTC"))
+		Expect(outputBuffer.String()).To(ContainSubstring("Europe/Berlin"))
+	})
+
+	It("does not panic on unexported fields", func() {
+		s := struct {
+			cutoff time.Time `env:"TIME_CUTOFF,report"`
+		}{time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)}
+
+		envstruct.ReportWriter = bytes.NewBuffer(nil)
+
+		Expect(func() { envstruct.ToEnv(&s) }).ToNot(Panic())
+		Expect(func() { envstruct.WriteReport(&s) }).ToNot(Panic())
+	})
+})
+
+var _ = DescribeTable("extended durations",
+	func(input string, expected time.Duration) {
+		os.Setenv("TIME_RETENTION", input)
+		defer os.Unsetenv("TIME_RETENTION")
+
+		var ts TimeTestStruct
+		Expect(envstruct.Load(&ts)).To(Succeed())
+		Expect(ts.Retention).To(Equal(expected))
+	},
+	Entry("days", "7d", 7*24*time.Hour),
+	Entry("weeks", "2w", 14*24*time.Hour),
+	Entry("fractional days", "1.5d", 36*time.Hour),
+	Entry("mixed units", "1d2h30m", 26*time.Hour+30*time.Minute),
+	Entry("standard units", "90m", 90*time.Minute),
+	Entry("negative", "-1d", -24*time.Hour),
+)
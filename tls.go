@@ -0,0 +1,163 @@
+package envstruct
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TLSConfig is a struct that is setup for envstruct to load the
+// configuration of a TLS client or server. Certificates, keys and CAs can
+// either be given as a path to a PEM file or as inline PEM. When a
+// certificate and key are given, Load validates that they match.
+type TLSConfig struct {
+	Cert         string           `env:"TLS_CERT"`
+	Key          Secret[string]   `env:"TLS_KEY"`
+	CA           string           `env:"TLS_CA"`
+	ServerName   string           `env:"TLS_SERVER_NAME,   report"`
+	MinVersion   TLSVersion       `env:"TLS_MIN_VERSION,   report"`
+	CipherSuites []TLSCipherSuite `env:"TLS_CIPHER_SUITES, report"`
+}
+
+// Config returns a *tls.Config for the loaded configuration. The CA bundle
+// is used for both RootCAs and ClientCAs. MinVersion defaults to TLS 1.2.
+func (c TLSConfig) Config() (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName: c.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if c.MinVersion != 0 {
+		cfg.MinVersion = uint16(c.MinVersion)
+	}
+
+	for _, s := range c.CipherSuites {
+		cfg.CipherSuites = append(cfg.CipherSuites, uint16(s))
+	}
+
+	if c.Cert != "" || c.Key.Value() != "" {
+		cert, err := c.certificate()
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.CA != "" {
+		ca, err := readPEMOrFile(c.CA)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("no certificates found in TLS CA")
+		}
+
+		cfg.RootCAs = pool
+		cfg.ClientCAs = pool
+	}
+
+	return cfg, nil
+}
+
+func (c TLSConfig) certificate() (tls.Certificate, error) {
+	if c.Cert == "" || c.Key.Value() == "" {
+		return tls.Certificate{}, errors.New("TLS certificate and key must be given together")
+	}
+
+	cert, err := readPEMOrFile(c.Cert)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	key, err := readPEMOrFile(c.Key.Value())
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.X509KeyPair(cert, key)
+}
+
+// validateLoad implements loadValidator.
+func (c *TLSConfig) validateLoad() error {
+	if c.Cert == "" && c.Key.Value() == "" {
+		return nil
+	}
+
+	_, err := c.certificate()
+	return err
+}
+
+// isPEM returns true if s is inline PEM rather than a path.
+func isPEM(s string) bool {
+	return strings.HasPrefix(strings.TrimSpace(s), "-----BEGIN ")
+}
+
+func readPEMOrFile(s string) ([]byte, error) {
+	if isPEM(s) {
+		return []byte(s), nil
+	}
+
+	return os.ReadFile(s)
+}
+
+// TLSVersion is a TLS version that Load parses from `1.0`, `1.1`, `1.2` or
+// `1.3`, optionally prefixed with `TLS`.
+type TLSVersion uint16
+
+var tlsVersions = map[string]TLSVersion{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// UnmarshalEnv implements Unmarshaller.
+func (v *TLSVersion) UnmarshalEnv(s string) error {
+	name := strings.TrimSpace(strings.TrimPrefix(strings.ToUpper(s), "TLS"))
+
+	version, ok := tlsVersions[name]
+	if !ok {
+		return fmt.Errorf("unknown TLS version %q", s)
+	}
+
+	*v = version
+
+	return nil
+}
+
+// String implements fmt.Stringer.
+func (v TLSVersion) String() string {
+	if v == 0 {
+		return ""
+	}
+
+	return tls.VersionName(uint16(v))
+}
+
+// TLSCipherSuite is a TLS cipher suite that Load parses from its name, e.g.
+// `TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256`.
+type TLSCipherSuite uint16
+
+// UnmarshalEnv implements Unmarshaller.
+func (c *TLSCipherSuite) UnmarshalEnv(s string) error {
+	suites := append(tls.CipherSuites(), tls.InsecureCipherSuites()...)
+	for _, suite := range suites {
+		if suite.Name == s {
+			*c = TLSCipherSuite(suite.ID)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown TLS cipher suite %q", s)
+}
+
+// String implements fmt.Stringer.
+func (c TLSCipherSuite) String() string {
+	return tls.CipherSuiteName(uint16(c))
+}
@@ -0,0 +1,188 @@
+package envstruct_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type TLSTestStruct struct {
+	TLS envstruct.TLSConfig
+}
+
+var tlsEnvVars = []string{
+	"TLS_CERT",
+	"TLS_KEY",
+	"TLS_CA",
+	"TLS_SERVER_NAME",
+	"TLS_MIN_VERSION",
+	"TLS_CIPHER_SUITES",
+}
+
+var _ = Describe("TLSConfig", func() {
+	var (
+		ts              TLSTestStruct
+		certPEM, keyPEM string
+		dir             string
+	)
+
+	BeforeEach(func() {
+		certPEM, keyPEM = generateCertificate("server.example.com")
+		dir = GinkgoT().TempDir()
+
+		ts = TLSTestStruct{}
+	})
+
+	AfterEach(func() {
+		for _, k := range tlsEnvVars {
+			os.Unsetenv(k)
+		}
+	})
+
+	It("loads inline PEM", func() {
+		os.Setenv("TLS_CERT", certPEM)
+		os.Setenv("TLS_KEY", keyPEM)
+		os.Setenv("TLS_CA", certPEM)
+		os.Setenv("TLS_SERVER_NAME", "server.example.com")
+		os.Setenv("TLS_MIN_VERSION", "1.3")
+		os.Setenv("TLS_CIPHER_SUITES", "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384")
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		cfg, err := ts.TLS.Config()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(cfg.Certificates).To(HaveLen(1))
+		Expect(cfg.RootCAs).ToNot(BeNil())
+		Expect(cfg.ClientCAs).ToNot(BeNil())
+		Expect(cfg.ServerName).To(Equal("server.example.com"))
+		Expect(cfg.MinVersion).To(Equal(uint16(tls.VersionTLS13)))
+		Expect(cfg.CipherSuites).To(Equal([]uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		}))
+	})
+
+	It("loads PEM files", func() {
+		certFile := filepath.Join(dir, "cert.pem")
+		keyFile := filepath.Join(dir, "key.pem")
+		Expect(os.WriteFile(certFile, []byte(certPEM), 0600)).To(Succeed())
+		Expect(os.WriteFile(keyFile, []byte(keyPEM), 0600)).To(Succeed())
+
+		os.Setenv("TLS_CERT", certFile)
+		os.Setenv("TLS_KEY", keyFile)
+		os.Setenv("TLS_CA", certFile)
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		cfg, err := ts.TLS.Config()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.Certificates).To(HaveLen(1))
+		Expect(cfg.RootCAs).ToNot(BeNil())
+	})
+
+	It("defaults to TLS 1.2 without certificates", func() {
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		cfg, err := ts.TLS.Config()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+		Expect(cfg.Certificates).To(BeEmpty())
+		Expect(cfg.RootCAs).To(BeNil())
+	})
+
+	It("returns an error when the key does not match the certificate", func() {
+		_, otherKeyPEM := generateCertificate("other.example.com")
+		os.Setenv("TLS_CERT", certPEM)
+		os.Setenv("TLS_KEY", otherKeyPEM)
+
+		Expect(envstruct.Load(&ts)).To(MatchError(ContainSubstring("private key does not match public key")))
+	})
+
+	It("returns an error when only the certificate is given", func() {
+		os.Setenv("TLS_CERT", certPEM)
+
+		Expect(envstruct.Load(&ts)).To(MatchError("TLS certificate and key must be given together"))
+	})
+
+	It("returns an error when a file does not exist", func() {
+		os.Setenv("TLS_CERT", filepath.Join(dir, "missing.pem"))
+		os.Setenv("TLS_KEY", keyPEM)
+
+		Expect(envstruct.Load(&ts)).ToNot(Succeed())
+	})
+
+	It("returns an error for an unknown TLS version", func() {
+		os.Setenv("TLS_MIN_VERSION", "1.4")
+
+		Expect(envstruct.Load(&ts)).To(MatchError(`unknown TLS version "1.4"`))
+	})
+
+	It("returns an error for an unknown cipher suite", func() {
+		os.Setenv("TLS_CIPHER_SUITES", "TLS_NOT_A_CIPHER")
+
+		Expect(envstruct.Load(&ts)).To(MatchError(`unknown TLS cipher suite "TLS_NOT_A_CIPHER"`))
+	})
+
+	It("returns an error for a CA without certificates", func() {
+		os.Setenv("TLS_CA", "-----BEGIN NOTHING-----\n-----END NOTHING-----\n")
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		_, err := ts.TLS.Config()
+		Expect(err).To(MatchError("no certificates found in TLS CA"))
+	})
+
+	It("formats the values with ToEnv", func() {
+		os.Setenv("TLS_MIN_VERSION", "TLS1.3")
+		os.Setenv("TLS_CIPHER_SUITES", "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256")
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(envstruct.ToEnv(&ts)).To(ContainElements(
+			"TLS_MIN_VERSION=TLS 1.3",
+			"TLS_CIPHER_SUITES=TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+		))
+	})
+})
+
+func generateCertificate(commonName string) (certPEM, keyPEM string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		Issuer:                pkix.Name{CommonName: commonName},
+		DNSNames:              []string{commonName},
+		NotBefore:             time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:              time.Date(2036, 1, 1, 0, 0, 0, 0, time.UTC),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).ToNot(HaveOccurred())
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	Expect(err).ToNot(HaveOccurred())
+
+	certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+
+	return certPEM, keyPEM
+}
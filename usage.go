@@ -0,0 +1,50 @@
+package envstruct
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"code.cloudfoundry.org/go-envstruct/internal/tags"
+)
+
+const tagDescription = tags.Description
+
+// WriteUsage will take a struct that is setup for envstruct and write a
+// description of every environment variable it reads to w, in the style of
+// flag.PrintDefaults. Each entry lists the environment variable exactly as
+// Load reads it, its type, the `description` struct tag and whether or not
+// it is required. Default values are taken from the current value of each
+// field and are omitted or redacted in the same way as WriteReport.
+func WriteUsage(w io.Writer, t interface{}, opts ...ReportOption) error {
+	return walkEnvFields(t, newReportOptions(opts), func(f envField) error {
+		var b strings.Builder
+
+		fmt.Fprintf(&b, "  %s %v\n", f.envVar, f.value.Type())
+		b.WriteString("    \t")
+		b.WriteString(strings.ReplaceAll(f.field.Tag.Get(tagDescription), "\n", "\n    \t"))
+
+		if f.required() {
+			b.WriteString(" (required)")
+		} else if v, ok := f.reportValue(); ok && !isZeroValue(f.value) {
+			fmt.Fprintf(&b, " (default %s)", v)
+		}
+
+		_, err := fmt.Fprintln(w, strings.TrimRight(b.String(), " \t\n"))
+		return err
+	})
+}
+
+func isZeroValue(v reflect.Value) bool {
+	if v.IsZero() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+
+	return false
+}
@@ -0,0 +1,63 @@
+package envstruct_test
+
+import (
+	"bytes"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type UsageTestStruct struct {
+	HostIP   string   `env:"HOST_IP,required,report" description:"IP address to listen on"`
+	HostPort int      `env:"HOST_PORT,report"        description:"port to listen on"`
+	Password string   `env:"PASSWORD"                description:"password for the admin user"`
+	Tags     []string `env:"TAGS,report"`
+	Sub      UsageTestSubStruct
+}
+
+type UsageTestSubStruct struct {
+	LogLevel string `env:"LOG_LEVEL,report" description:"one of debug, info or error"`
+}
+
+var _ = Describe("WriteUsage()", func() {
+	It("writes the usage of every environment variable", func() {
+		ts := UsageTestStruct{
+			HostPort: 8080,
+			Password: "default-password",
+			Sub: UsageTestSubStruct{
+				LogLevel: "info",
+			},
+		}
+
+		outputBuffer := bytes.NewBuffer(nil)
+		Expect(envstruct.WriteUsage(outputBuffer, &ts)).To(Succeed())
+
+		Expect(outputBuffer.String()).To(Equal(expectedUsageOutput))
+	})
+
+	It("prints environment variables as Load reads them", func() {
+		ts := struct {
+			Port int `env:"port" description:"port to listen on"`
+		}{}
+
+		outputBuffer := bytes.NewBuffer(nil)
+		Expect(envstruct.WriteUsage(outputBuffer, &ts)).To(Succeed())
+
+		Expect(outputBuffer.String()).To(Equal("  port int\n    \tport to listen on\n"))
+	})
+})
+
+const (
+	expectedUsageOutput = `  HOST_IP string
+    	IP address to listen on (required)
+  HOST_PORT int
+    	port to listen on (default 8080)
+  PASSWORD string
+    	password for the admin user
+  TAGS []string
+  LOG_LEVEL string
+    	one of debug, info or error (default info)
+`
+)
@@ -0,0 +1,210 @@
+package envstruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"code.cloudfoundry.org/go-envstruct/internal/tags"
+)
+
+// FieldError describes a problem with a field of a struct found by Check.
+type FieldError struct {
+	// Field is the path to the field from the root struct, e.g.
+	// `Config.Database.Password`.
+	Field string
+
+	// EnvVar is the environment variable the field is read from.
+	EnvVar string
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	if e.EnvVar == "" {
+		return fmt.Sprintf("%s: %s", e.Field, e.Err)
+	}
+
+	return fmt.Sprintf("%s (%s): %s", e.Field, e.EnvVar, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks that the struct t points to can be loaded, without reading
+// the environment. See Check.
+func Validate(t interface{}) error {
+	if _, err := targetStruct(t); err != nil {
+		return err
+	}
+
+	return Check(reflect.TypeOf(t))
+}
+
+// Check checks that a struct type, or a pointer to one, can be loaded,
+// without reading the environment. It walks the fields in the same way as
+// Load and reports fields of unsupported types, environment variables read
+// by more than one field, unknown, malformed or conflicting `env` tag options
+// and structs with an `env` tag but no UnmarshalEnv method. Pointers to
+// structs with an `env` tag are accepted, as Load loads their fields. Every
+// problem is returned as a FieldError, joined with errors.Join.
+func Check(t reflect.Type) error {
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return &InvalidTargetError{Type: t}
+	}
+
+	c := checker{envVars: map[string]string{}}
+	c.checkStruct(t, t.Name(), "", map[reflect.Type]bool{})
+
+	return errors.Join(c.errs...)
+}
+
+type checker struct {
+	envVars map[string]string
+	errs    []error
+}
+
+func (c *checker) checkStruct(t reflect.Type, path, prefix string, visiting map[reflect.Type]bool) {
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		typeField := t.Field(i)
+		fieldPath := path + "." + typeField.Name
+
+		tagProperties := envTagProperties(typeField.Tag, prefix)
+		envVar := tagProperties[indexEnvVar]
+
+		fail := func(err error) {
+			c.errs = append(c.errs, &FieldError{Field: fieldPath, EnvVar: envVar, Err: err})
+		}
+
+		if _, ok := typeField.Tag.Lookup(tags.Env); ok {
+			for _, err := range checkOptions(typeField.Type, tagProperties[1:]) {
+				fail(err)
+			}
+		}
+
+		if !hasEnvTag(tagProperties) {
+			nested, ok := nestedStructType(typeField.Type)
+			if ok && !visiting[nested] {
+				c.checkStruct(nested, fieldPath, prefix+typeField.Tag.Get(tagEnvPrefix), visiting)
+			}
+
+			continue
+		}
+
+		if !typeField.IsExported() {
+			fail(errors.New("env tag on unexported field is ignored"))
+			continue
+		}
+
+		if first, ok := c.envVars[envVar]; ok {
+			fail(fmt.Errorf("environment variable is also read by %s", first))
+		} else {
+			c.envVars[envVar] = fieldPath
+		}
+
+		if _, ok := nestedStructType(typeField.Type); ok && typeField.Type.Kind() != reflect.Pointer {
+			fail(nestedStructError(typeField.Type))
+			continue
+		}
+
+		if !supportedType(typeField.Type) {
+			fail(fmt.Errorf("unsupported type %s", typeField.Type))
+		}
+	}
+}
+
+// nestedStructType returns the struct type of a struct or pointer to struct
+// whose fields are loaded individually.
+func nestedStructType(t reflect.Type) (reflect.Type, bool) {
+	if isLeafType(t) {
+		return nil, false
+	}
+
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || isLeafType(t) {
+		return nil, false
+	}
+
+	return t, true
+}
+
+func nestedStructError(t reflect.Type) error {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return fmt.Errorf("nested struct %s with env tag needs to have an UnmarshallEnv method", t.Name())
+}
+
+// supportedType returns true if setField can set a value of type t.
+func supportedType(t reflect.Type) bool {
+	if isLeafType(t) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64,
+		reflect.Complex64, reflect.Complex128,
+		reflect.Interface:
+		return true
+	case reflect.Pointer:
+		return t.Elem().Kind() == reflect.Struct || supportedType(t.Elem())
+	case reflect.Slice, reflect.Array:
+		return supportedType(t.Elem())
+	case reflect.Map:
+		return supportedType(t.Key()) && supportedType(t.Elem())
+	}
+
+	return false
+}
+
+// checkOptions returns an error for every unknown, malformed or conflicting
+// option of the `env` tag of a field of type t.
+func checkOptions(t reflect.Type, options []string) []error {
+	elem := elemType(t)
+
+	var errs []error
+	for _, err := range tags.Check(tags.Type{
+		Pointer:  t.Kind() == reflect.Pointer,
+		Time:     elem == reflect.TypeOf(time.Time{}),
+		Duration: elem == reflect.TypeOf(time.Duration(0)),
+		Bytes:    isBytes(elem),
+	}, options) {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// elemType returns the type of the values of a field of type t, looking
+// through pointers, Secret and, unless t holds bytes, slices and arrays.
+func elemType(t reflect.Type) reflect.Type {
+	for {
+		switch {
+		case t.Kind() == reflect.Pointer:
+			t = t.Elem()
+		case t.Kind() == reflect.Struct && t.Implements(revealerType):
+			t = t.Field(0).Type
+		case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && !isBytes(t):
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package envstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+	"code.cloudfoundry.org/go-envstruct/internal/gotypes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type ValidateDatabase struct {
+	Host string `env:"HOST,required"`
+	Port int    `env:"PORT"`
+}
+
+type ValidTestStruct struct {
+	Name     string                   `env:"NAME,required,report"`
+	Timeout  time.Duration            `env:"TIMEOUT,extended"`
+	Cutoffs  []time.Time              `env:"CUTOFFS,layout=2006-01-02"`
+	Key      [32]byte                 `env:"KEY,encoding=hex"`
+	Password envstruct.Secret[string] `env:"PASSWORD,mask=last4"`
+	Token    envstruct.Secret[[]byte] `env:"TOKEN,encoding=hex"`
+	Posted   time.Time                `env:"POSTED,layout='Mon, 02 Jan 2006'"`
+	Port     *int                     `env:"OPTIONAL_PORT,optional"`
+	Labels   map[string]int           `env:"LABELS"`
+	DB       ValidateDatabase         `envprefix:"DB_"`
+	Cache    *ValidateDatabase        `env:",optional" envprefix:"CACHE_"`
+	Replica  *ValidateDatabase        `env:"REPLICA"`
+	Next     *ValidTestStruct         `env:",optional" envprefix:"NEXT_"`
+	Ignored  chan int
+}
+
+type InvalidTestStruct struct {
+	Chan      chan int          `env:"CHAN"`
+	Nested    ValidateDatabase  `env:"NESTED"`
+	Host      string            `env:"DB_HOST"`
+	DB        ValidateDatabase  `envprefix:"DB_"`
+	Typo      string            `env:"TYPO,requierd"`
+	Mask      string            `env:"MASK,mask"`
+	Redacted  string            `env:"REDACTED,hash,mask=last4"`
+	Optional  *int              `env:"OPTIONAL,required,optional"`
+	Layout    time.Duration     `env:"LAYOUT,layout=2006"`
+	Encoding  []byte            `env:"ENCODING,encoding=base32"`
+	MaskValue string            `env:"MASK_VALUE,mask=first4"`
+	unexposed string            `env:"UNEXPORTED"`
+	Map       map[string]func() `env:"MAP"`
+}
+
+var _ = Describe("Validate", func() {
+	It("accepts valid structs", func() {
+		Expect(envstruct.Validate(&ValidTestStruct{})).To(Succeed())
+		Expect(envstruct.Check(reflect.TypeOf(ValidTestStruct{}))).To(Succeed())
+		Expect(envstruct.Check(reflect.TypeOf(&ValidTestStruct{}))).To(Succeed())
+	})
+
+	It("returns every problem as a FieldError", func() {
+		err := envstruct.Check(reflect.TypeOf(InvalidTestStruct{}))
+
+		var fieldErr *envstruct.FieldError
+		Expect(errors.As(err, &fieldErr)).To(BeTrue())
+		Expect(fieldErr.Field).To(Equal("InvalidTestStruct.Chan"))
+		Expect(fieldErr.EnvVar).To(Equal("CHAN"))
+
+		Expect(err.Error()).To(Equal(`InvalidTestStruct.Chan (CHAN): unsupported type chan int
+InvalidTestStruct.Nested (NESTED): nested struct ValidateDatabase with env tag needs to have an UnmarshallEnv method
+InvalidTestStruct.DB.Host (DB_HOST): environment variable is also read by InvalidTestStruct.Host
+InvalidTestStruct.Typo (TYPO): unknown env tag option "requierd"
+InvalidTestStruct.Mask (MASK): env tag option "mask" requires a value
+InvalidTestStruct.Redacted (REDACTED): env tag options "mask", "hash" and "redact-userinfo" conflict
+InvalidTestStruct.Optional (OPTIONAL): env tag options "required" and "optional" conflict
+InvalidTestStruct.Layout (LAYOUT): env tag option "layout" only applies to time.Time
+InvalidTestStruct.Encoding (ENCODING): env tag option "encoding" has invalid value "base32"
+InvalidTestStruct.MaskValue (MASK_VALUE): env tag option "mask" has invalid value "first4"
+InvalidTestStruct.unexposed (UNEXPORTED): env tag on unexported field is ignored
+InvalidTestStruct.Map (MAP): unsupported type map[string]func()`))
+	})
+
+	It("returns an error for non-struct types", func() {
+		var targetErr *envstruct.InvalidTargetError
+
+		Expect(errors.As(envstruct.Check(reflect.TypeOf(1)), &targetErr)).To(BeTrue())
+		Expect(errors.As(envstruct.Check(nil), &targetErr)).To(BeTrue())
+		Expect(errors.As(envstruct.Validate(ValidTestStruct{}), &targetErr)).To(BeTrue())
+	})
+
+	It("knows the same built-in types as envstructlint", func() {
+		Expect(gotypes.BuiltinTypes()).To(ConsistOf(envstruct.BuiltinTypeNames()))
+	})
+})
@@ -0,0 +1,250 @@
+package envstruct
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	certificateType      = reflect.TypeOf(&x509.Certificate{})
+	certificateSliceType = reflect.TypeOf([]*x509.Certificate{})
+	certPoolType         = reflect.TypeOf(&x509.CertPool{})
+	privateKeyType       = reflect.TypeOf((*crypto.PrivateKey)(nil)).Elem()
+)
+
+// readPEMBundle reads the PEM blocks from input, which is either inline PEM
+// or a comma separated list of paths to PEM files.
+func readPEMBundle(input string) ([]byte, error) {
+	if isPEM(input) {
+		return []byte(input), nil
+	}
+
+	var bundle []byte
+	for _, path := range separateOnComma(input) {
+		b, err := readPEMOrFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		bundle = append(bundle, b...)
+		bundle = append(bundle, '\n')
+	}
+
+	return bundle, nil
+}
+
+func parseCertificates(input string) ([]*x509.Certificate, error) {
+	rest, err := readPEMBundle(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates found")
+	}
+
+	return certs, nil
+}
+
+func setCertificate(value reflect.Value, input string) error {
+	certs, err := parseCertificates(input)
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.ValueOf(certs[0]))
+
+	return nil
+}
+
+func setCertificates(value reflect.Value, input string) error {
+	certs, err := parseCertificates(input)
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.ValueOf(certs))
+
+	return nil
+}
+
+func setCertPool(value reflect.Value, input string) error {
+	certs, err := parseCertificates(input)
+	if err != nil {
+		return err
+	}
+
+	pool := x509.NewCertPool()
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+
+	value.Set(reflect.ValueOf(pool))
+
+	return nil
+}
+
+func setPrivateKey(value reflect.Value, input string) error {
+	b, err := readPEMOrFile(input)
+	if err != nil {
+		return err
+	}
+
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			return errors.New("no private key found")
+		}
+
+		var key crypto.PrivateKey
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		value.Set(reflect.ValueOf(&key).Elem())
+
+		return nil
+	}
+}
+
+func formatCertificate(value reflect.Value, _ []string) string {
+	if !value.CanInterface() {
+		return omittedValue
+	}
+
+	cert, _ := value.Interface().(*x509.Certificate)
+	if cert == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: cert.Raw,
+	})))
+}
+
+func formatCertificates(value reflect.Value, tagProperties []string) string {
+	parts := make([]string, value.Len())
+	for i := range parts {
+		parts[i] = formatCertificate(value.Index(i), tagProperties)
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// formatCertPool writes nothing as the certificates of a pool can not be
+// retrieved.
+func formatCertPool(reflect.Value, []string) string {
+	return ""
+}
+
+func formatPrivateKey(value reflect.Value, _ []string) string {
+	if value.IsNil() {
+		return ""
+	}
+
+	if !value.CanInterface() {
+		return omittedValue
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(value.Interface())
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: der,
+	})))
+}
+
+func summarizeCertificate(value reflect.Value) string {
+	cert, _ := value.Interface().(*x509.Certificate)
+	if cert == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("subject=%q issuer=%q expires=%s",
+		cert.Subject,
+		cert.Issuer,
+		cert.NotAfter.UTC().Format(time.RFC3339),
+	)
+}
+
+func summarizeCertificates(value reflect.Value) string {
+	parts := make([]string, value.Len())
+	for i := range parts {
+		parts[i] = summarizeCertificate(value.Index(i))
+	}
+
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+func summarizeCertPool(value reflect.Value) string {
+	pool, _ := value.Interface().(*x509.CertPool)
+	if pool == nil {
+		return "<nil>"
+	}
+
+	var subjects []string
+	// Subjects is only deprecated for the system pool, which is never loaded.
+	for _, raw := range pool.Subjects() {
+		var rdn pkix.RDNSequence
+		if _, err := asn1.Unmarshal(raw, &rdn); err != nil {
+			continue
+		}
+
+		var name pkix.Name
+		name.FillFromRDNSequence(&rdn)
+		subjects = append(subjects, fmt.Sprintf("subject=%q", name))
+	}
+
+	return "[" + strings.Join(subjects, ", ") + "]"
+}
+
+func summarizePrivateKey(value reflect.Value) string {
+	if value.IsNil() {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("(%T)", value.Interface())
+}
@@ -0,0 +1,178 @@
+package envstruct_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"strings"
+
+	envstruct "code.cloudfoundry.org/go-envstruct"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type X509TestStruct struct {
+	Cert  *x509.Certificate   `env:"X509_CERT"`
+	Chain []*x509.Certificate `env:"X509_CHAIN"`
+	Pool  *x509.CertPool      `env:"X509_POOL"`
+	Key   crypto.PrivateKey   `env:"X509_KEY,report"`
+}
+
+var x509EnvVars = []string{
+	"X509_CERT",
+	"X509_CHAIN",
+	"X509_POOL",
+	"X509_KEY",
+}
+
+var _ = Describe("x509 types", func() {
+	var (
+		ts                  X509TestStruct
+		certPEM, keyPEM     string
+		otherCertPEM        string
+		certFile, otherFile string
+	)
+
+	BeforeEach(func() {
+		certPEM, keyPEM = generateCertificate("server.example.com")
+		otherCertPEM, _ = generateCertificate("ca.example.com")
+
+		dir := GinkgoT().TempDir()
+		certFile = filepath.Join(dir, "cert.pem")
+		otherFile = filepath.Join(dir, "other.pem")
+		Expect(os.WriteFile(certFile, []byte(certPEM), 0600)).To(Succeed())
+		Expect(os.WriteFile(otherFile, []byte(otherCertPEM), 0600)).To(Succeed())
+
+		ts = X509TestStruct{}
+	})
+
+	AfterEach(func() {
+		for _, k := range x509EnvVars {
+			os.Unsetenv(k)
+		}
+	})
+
+	It("loads inline PEM", func() {
+		os.Setenv("X509_CERT", certPEM)
+		os.Setenv("X509_CHAIN", certPEM+otherCertPEM)
+		os.Setenv("X509_POOL", certPEM+otherCertPEM)
+		os.Setenv("X509_KEY", keyPEM)
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(ts.Cert.Subject.CommonName).To(Equal("server.example.com"))
+		Expect(ts.Chain).To(HaveLen(2))
+		Expect(ts.Chain[1].Subject.CommonName).To(Equal("ca.example.com"))
+		Expect(ts.Pool.Equal(poolOf(ts.Chain...))).To(BeTrue())
+		Expect(ts.Key).To(BeAssignableToTypeOf(&ecdsa.PrivateKey{}))
+		Expect(ts.Key.(*ecdsa.PrivateKey).Public()).To(Equal(ts.Cert.PublicKey))
+	})
+
+	It("loads PEM files", func() {
+		keyFile := filepath.Join(GinkgoT().TempDir(), "key.pem")
+		Expect(os.WriteFile(keyFile, []byte(keyPEM), 0600)).To(Succeed())
+
+		os.Setenv("X509_CERT", certFile)
+		os.Setenv("X509_CHAIN", certFile+","+otherFile)
+		os.Setenv("X509_POOL", otherFile)
+		os.Setenv("X509_KEY", keyFile)
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		Expect(ts.Cert.Subject.CommonName).To(Equal("server.example.com"))
+		Expect(ts.Chain).To(HaveLen(2))
+		Expect(ts.Pool.Equal(poolOf(ts.Chain[1]))).To(BeTrue())
+		Expect(ts.Key).ToNot(BeNil())
+	})
+
+	DescribeTable("returns an error for invalid values",
+		func(envVar string, value func() string) {
+			os.Setenv(envVar, value())
+
+			Expect(envstruct.Load(&ts)).ToNot(Succeed())
+		},
+		Entry("missing file", "X509_CERT", func() string { return "/does/not/exist.pem" }),
+		Entry("no certificate", "X509_CHAIN", func() string { return keyPEM }),
+		Entry("invalid certificate", "X509_POOL", func() string {
+			return "-----BEGIN CERTIFICATE-----\nbm90IGEgY2VydA==\n-----END CERTIFICATE-----\n"
+		}),
+		Entry("no private key", "X509_KEY", func() string { return certPEM }),
+	)
+
+	It("displays a summary in the report without key material", func() {
+		os.Setenv("X509_CERT", certPEM)
+		os.Setenv("X509_CHAIN", certPEM)
+		os.Setenv("X509_POOL", otherCertPEM)
+		os.Setenv("X509_KEY", keyPEM)
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		outputBuffer := bytes.NewBuffer(nil)
+		envstruct.ReportWriter = outputBuffer
+
+		Expect(envstruct.WriteReport(&ts)).To(Succeed())
+
+		output := outputBuffer.String()
+		Expect(output).To(ContainSubstring(`subject="CN=server.example.com" issuer="CN=server.example.com" expires=2036-01-01T00:00:00Z`))
+		Expect(output).To(ContainSubstring(`[subject="CN=ca.example.com"]`))
+		Expect(output).To(ContainSubstring("(*ecdsa.PrivateKey)"))
+		Expect(output).ToNot(ContainSubstring("(OMITTED)"))
+		Expect(output).ToNot(ContainSubstring("BEGIN"))
+	})
+
+	It("formats the values as PEM with ToEnv", func() {
+		os.Setenv("X509_CERT", certPEM)
+		os.Setenv("X509_CHAIN", certPEM+otherCertPEM)
+		os.Setenv("X509_KEY", keyPEM)
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		env := envstruct.ToEnv(&ts)
+		Expect(env).To(ContainElements(
+			"X509_CERT="+strings.TrimSpace(certPEM),
+			"X509_CHAIN="+strings.TrimSpace(certPEM)+"\n"+strings.TrimSpace(otherCertPEM),
+			"X509_KEY="+strings.TrimSpace(keyPEM),
+		))
+	})
+
+	It("leaves cert pools out of ToEnv", func() {
+		os.Setenv("X509_POOL", otherCertPEM)
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		for _, kv := range envstruct.ToEnv(&ts) {
+			Expect(kv).ToNot(HavePrefix("X509_POOL="))
+		}
+	})
+
+	It("does not panic on unexported fields", func() {
+		os.Setenv("X509_CERT", certPEM)
+		os.Setenv("X509_CHAIN", certPEM+otherCertPEM)
+		os.Setenv("X509_KEY", keyPEM)
+
+		Expect(envstruct.Load(&ts)).To(Succeed())
+
+		s := struct {
+			cert  *x509.Certificate   `env:"X509_CERT,report"`
+			chain []*x509.Certificate `env:"X509_CHAIN,report"`
+			key   crypto.PrivateKey   `env:"X509_KEY,report"`
+		}{ts.Cert, ts.Chain, ts.Key}
+
+		envstruct.ReportWriter = bytes.NewBuffer(nil)
+
+		Expect(func() { envstruct.ToEnv(&s) }).ToNot(Panic())
+		Expect(func() { envstruct.WriteReport(&s) }).ToNot(Panic())
+	})
+})
+
+func poolOf(certs ...*x509.Certificate) *x509.CertPool {
+	pool := x509.NewCertPool()
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+	return pool
+}